@@ -0,0 +1,155 @@
+package logtic
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy describes what happens when an event is written while the queue of an asynchronous logging instance
+// is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the caller until there is room in the queue. No events are dropped.
+	OverflowBlock = OverflowPolicy(0)
+	// OverflowDropNewest drops the event being written
+	OverflowDropNewest = OverflowPolicy(1)
+	// OverflowDropOldest drops the oldest event in the queue to make room for the event being written
+	OverflowDropOldest = OverflowPolicy(2)
+)
+
+// AsyncOptions describe how events are written in the background by a logging instance
+type AsyncOptions struct {
+	// Enabled will write events in the background. Events are added to a queue and written to the console, log file,
+	// and sinks by a separate goroutine. Fatal and panic events are always written immediately, after all pending
+	// events.
+	Enabled bool
+	// QueueSize is the maximum number of events waiting to be written. Defaults to 1024.
+	QueueSize int
+	// Overflow describes what happens when the queue is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+}
+
+// DroppedEvents returns the number of events that were dropped because the queue was full
+func (l *Logger) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+type tEventQueue struct {
+	lock     sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	events   []Event
+	head     int
+	count    int
+	busy     bool
+	closed   bool
+	overflow OverflowPolicy
+	done     chan struct{}
+}
+
+func (l *Logger) startQueue() *tEventQueue {
+	size := l.Async.QueueSize
+	if size <= 0 {
+		size = 1024
+	}
+
+	q := &tEventQueue{
+		events:   make([]Event, size),
+		overflow: l.Async.Overflow,
+		done:     make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.lock)
+	q.notFull = sync.NewCond(&q.lock)
+	q.idle = sync.NewCond(&q.lock)
+
+	go q.run(func(event Event) {
+		defer l.panicRecover(event.Source)
+		l.writeConsole(event)
+		l.write(event)
+	})
+	return q
+}
+
+// push adds the event to the queue, returning false if the queue is closed and the event must be written by the caller
+func (q *tEventQueue) push(event Event, dropped *uint64) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for q.count == len(q.events) && !q.closed {
+		switch q.overflow {
+		case OverflowDropNewest:
+			atomic.AddUint64(dropped, 1)
+			return true
+		case OverflowDropOldest:
+			q.events[q.head] = Event{}
+			q.head = (q.head + 1) % len(q.events)
+			q.count--
+			atomic.AddUint64(dropped, 1)
+		default:
+			q.notFull.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+
+	q.events[(q.head+q.count)%len(q.events)] = event
+	q.count++
+	q.notEmpty.Signal()
+	return true
+}
+
+func (q *tEventQueue) run(write func(event Event)) {
+	defer close(q.done)
+
+	for {
+		q.lock.Lock()
+		for q.count == 0 && !q.closed {
+			q.busy = false
+			q.idle.Broadcast()
+			q.notEmpty.Wait()
+		}
+		if q.count == 0 {
+			q.busy = false
+			q.idle.Broadcast()
+			q.lock.Unlock()
+			return
+		}
+
+		batch := make([]Event, q.count)
+		for i := range batch {
+			batch[i] = q.events[(q.head+i)%len(q.events)]
+			q.events[(q.head+i)%len(q.events)] = Event{}
+		}
+		q.head = 0
+		q.count = 0
+		q.busy = true
+		q.notFull.Broadcast()
+		q.lock.Unlock()
+
+		for _, event := range batch {
+			write(event)
+		}
+	}
+}
+
+// flush waits for all events in the queue to be written
+func (q *tEventQueue) flush() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for q.count > 0 || q.busy {
+		q.idle.Wait()
+	}
+}
+
+// close writes all pending events and stops the queue
+func (q *tEventQueue) close() {
+	q.lock.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.lock.Unlock()
+	<-q.done
+}
@@ -0,0 +1,201 @@
+package logtic_test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type blockingSink struct {
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+	events  []string
+}
+
+func (s *blockingSink) WriteEvent(event logtic.Event) error {
+	s.once.Do(func() {
+		close(s.entered)
+		<-s.release
+	})
+	s.events = append(s.events, event.Message)
+	return nil
+}
+
+func TestAsync(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Async.Enabled = true
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			source := logtic.Log.Connect("test")
+			for y := 0; y < 500; y++ {
+				source.Info("Count %d", y)
+			}
+		}()
+	}
+	wg.Wait()
+	logtic.Log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2000 {
+		t.Errorf("Unexpected number of lines in log file. Expected 2000 got %d", lines)
+	}
+	if dropped := logtic.Log.DroppedEvents(); dropped != 0 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestAsyncOverflow(t *testing.T) {
+	test := func(overflow logtic.OverflowPolicy, expected []string) {
+		Setup()
+
+		logtic.Log.Level = logtic.LevelDebug
+		logtic.Log.Async = logtic.AsyncOptions{
+			Enabled:   true,
+			QueueSize: 2,
+			Overflow:  overflow,
+		}
+		if err := logtic.Log.Open(); err != nil {
+			t.Fatalf("Error opening log file: %s", err.Error())
+		}
+
+		sink := &blockingSink{
+			entered: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+		source := logtic.Log.Connect("test")
+		source.Info("1")
+		<-sink.entered
+		for _, message := range []string{"2", "3", "4", "5", "6"} {
+			source.Info(message)
+		}
+		if dropped := logtic.Log.DroppedEvents(); dropped != 3 {
+			t.Errorf("Unexpected number of dropped events. Expected 3 got %d", dropped)
+		}
+		close(sink.release)
+		logtic.Log.Flush()
+
+		if strings.Join(sink.events, ",") != strings.Join(expected, ",") {
+			t.Errorf("Unexpected events written. Expected %v got %v", expected, sink.events)
+		}
+		logtic.Log.Close()
+	}
+
+	test(logtic.OverflowDropNewest, []string{"1", "2", "3"})
+	test(logtic.OverflowDropOldest, []string{"1", "5", "6"})
+}
+
+func TestAsyncPanic(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Async.Enabled = true
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	source := logtic.Log.Connect("test")
+	source.Info("Before panic")
+	func() {
+		defer func() {
+			recover()
+		}()
+		source.Panic("Ahh!")
+	}()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if !strings.Contains(string(data), "Before panic\n") || !strings.HasSuffix(string(data), "[FATAL][test] Ahh!\n") {
+		t.Errorf("Log file does not contain expected events: '%s'", data)
+	}
+	logtic.Log.Close()
+}
+
+func TestAsyncReusedParameters(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Formatter = &logtic.JSONFormatter{}
+	logtic.Log.Async.Enabled = true
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	// The map is changed after each event is written, which must not affect events waiting in the queue
+	source := logtic.Log.Connect("test")
+	parameters := map[string]any{}
+	for i := 0; i < 100; i++ {
+		parameters["i"] = i
+		source.PInfo("Count", parameters)
+	}
+	logtic.Log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 100 || !strings.Contains(lines[0], `"i":0`) || !strings.Contains(lines[99], `"i":99`) {
+		t.Errorf("Unexpected events in log file: '%s'", data)
+	}
+}
+
+func TestAsyncPanicRecover(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Async.Enabled = true
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	logtic.Log.AddSink(panicSink{}, logtic.LevelDebug)
+
+	// A panic writing one event must not stop the queue from writing the next
+	source := logtic.Log.Connect("test")
+	source.Info("first")
+	source.Info("second")
+	logtic.Log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	recovered := "[ERROR][test] logtic: recovered from panic writing event: sink panic\n\t"
+	if n := strings.Count(string(data), recovered); n != 2 {
+		t.Errorf("Unexpected number of recovered panics in log file. Expected 2 got %d:\n%s", n, data)
+	}
+	if !strings.Contains(string(data), "[INFO][test] second\n") {
+		t.Errorf("Event not written to log file:\n%s", data)
+	}
+}
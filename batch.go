@@ -0,0 +1,421 @@
+package logtic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchOptions describe how a sink that sends events over the network groups events into batches. Events are sent in
+// the background when a batch is full or when the interval has passed since the last batch was sent.
+type BatchOptions struct {
+	// MaxEvents is the maximum number of events sent in a single batch. Defaults to 512.
+	MaxEvents int
+	// Interval is the maximum amount of time an event waits before it is sent. Defaults to 1 second.
+	Interval time.Duration
+	// QueueSize is the maximum number of events waiting to be sent, including while a batch is being retried. Defaults
+	// to 8192.
+	QueueSize int
+	// Overflow describes what happens when the queue is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+}
+
+// RetryPolicy describes how a sink that sends events over the network retries a batch that could not be sent. Only
+// network errors and responses indicating a temporary failure, such as 429 Too Many Requests or 503 Service
+// Unavailable, are retried. If a response includes a Retry-After header, it is used instead of the backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a batch is sent before it is dropped. Defaults to 5.
+	MaxAttempts int
+	// InitialBackoff is the amount of time to wait before the first retry, doubling for each retry after that.
+	// Defaults to 500 milliseconds.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum amount of time to wait before a retry. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// defaultBatchClient is the HTTP client used by sinks that send batches of events when no client is set. The timeout
+// bounds how long a server that is not responding can delay closing the sink.
+var defaultBatchClient = &http.Client{Timeout: 30 * time.Second}
+
+// tRetryableError is an error from sending a batch that should be retried. If events is set, only those events are
+// retried.
+type tRetryableError struct {
+	err        error
+	retryAfter time.Duration
+	events     []Event
+}
+
+func (e *tRetryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tRetryableError) Unwrap() error {
+	return e.err
+}
+
+// tBatchSink implements the methods shared by sinks that send events in batches, and is embedded in those sinks
+type tBatchSink struct {
+	batcher *tBatcher
+	lock    sync.Mutex
+}
+
+// write adds the event to the next batch, using newBatcher to start the batcher when the first event is written
+func (s *tBatchSink) write(event Event, newBatcher func() *tBatcher) {
+	s.lock.Lock()
+	if s.batcher == nil {
+		s.batcher = newBatcher()
+	}
+	batcher := s.batcher
+	s.lock.Unlock()
+
+	batcher.push(event)
+}
+
+// Flush waits for all pending events to be sent
+func (s *tBatchSink) Flush() {
+	s.lock.Lock()
+	batcher := s.batcher
+	s.lock.Unlock()
+	if batcher != nil {
+		batcher.flush()
+	}
+}
+
+// Close sends all pending events and stops the sink. Events written after the sink is closed are dropped.
+func (s *tBatchSink) Close() error {
+	s.lock.Lock()
+	batcher := s.batcher
+	s.lock.Unlock()
+	if batcher != nil {
+		batcher.close()
+	}
+	return nil
+}
+
+// DroppedEvents returns the number of events that were dropped because the queue was full or they could not be sent
+func (s *tBatchSink) DroppedEvents() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.batcher == nil {
+		return 0
+	}
+	return s.batcher.droppedEvents()
+}
+
+// tBatcher collects events and sends them in batches from a background goroutine
+type tBatcher struct {
+	// dropped is first so that it is 64-bit aligned for atomic operations on 32-bit platforms
+	dropped uint64
+	name    string
+	options BatchOptions
+	retry   RetryPolicy
+	send    func(events []Event) error
+
+	lock    sync.Mutex
+	notFull *sync.Cond
+	idle    *sync.Cond
+	events  []Event
+	busy    bool
+	closed  bool
+	wake    chan struct{}
+	closing chan struct{}
+	done    chan struct{}
+}
+
+// newBatcher starts a new batcher that sends batches of events using send. The name is used in error messages.
+func newBatcher(name string, options BatchOptions, retry RetryPolicy, send func(events []Event) error) *tBatcher {
+	if options.MaxEvents <= 0 {
+		options.MaxEvents = 512
+	}
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 8192
+	}
+	if options.QueueSize < options.MaxEvents {
+		options.QueueSize = options.MaxEvents
+	}
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 5
+	}
+	if retry.InitialBackoff <= 0 {
+		retry.InitialBackoff = 500 * time.Millisecond
+	}
+	if retry.MaxBackoff <= 0 {
+		retry.MaxBackoff = 30 * time.Second
+	}
+
+	b := &tBatcher{
+		name:    name,
+		options: options,
+		retry:   retry,
+		send:    send,
+		wake:    make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	b.notFull = sync.NewCond(&b.lock)
+	b.idle = sync.NewCond(&b.lock)
+	go b.run()
+	return b
+}
+
+// push adds the event to the next batch
+func (b *tBatcher) push(event Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for len(b.events) >= b.options.QueueSize && !b.closed {
+		switch b.options.Overflow {
+		case OverflowDropNewest:
+			atomic.AddUint64(&b.dropped, 1)
+			return
+		case OverflowDropOldest:
+			b.events[0] = Event{}
+			b.events = b.events[1:]
+			atomic.AddUint64(&b.dropped, 1)
+		default:
+			b.notFull.Wait()
+		}
+	}
+	if b.closed {
+		atomic.AddUint64(&b.dropped, 1)
+		return
+	}
+
+	b.events = append(b.events, event)
+	if len(b.events) >= b.options.MaxEvents {
+		b.signal()
+	}
+}
+
+func (b *tBatcher) signal() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *tBatcher) run() {
+	defer close(b.done)
+
+	timer := time.NewTimer(b.options.Interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-b.wake:
+		case <-timer.C:
+		}
+
+		for {
+			b.lock.Lock()
+			if len(b.events) == 0 {
+				b.busy = false
+				b.idle.Broadcast()
+				closed := b.closed
+				b.lock.Unlock()
+				if closed {
+					return
+				}
+				break
+			}
+
+			n := len(b.events)
+			if n > b.options.MaxEvents {
+				n = b.options.MaxEvents
+			}
+			batch := make([]Event, n)
+			copy(batch, b.events)
+			b.events = append(make([]Event, 0, len(b.events)-n), b.events[n:]...)
+			b.busy = true
+			b.notFull.Broadcast()
+			b.lock.Unlock()
+
+			b.sendWithRetry(batch)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(b.options.Interval)
+	}
+}
+
+func (b *tBatcher) sendWithRetry(batch []Event) {
+	backoff := b.retry.InitialBackoff
+	closing := false
+	for attempt := 1; ; attempt++ {
+		err := b.safeSend(batch)
+		if err == nil {
+			return
+		}
+
+		var retryable *tRetryableError
+		if !errors.As(err, &retryable) || attempt >= b.retry.MaxAttempts || closing {
+			b.drop(batch, err)
+			return
+		}
+
+		if retryable.events != nil {
+			batch = retryable.events
+		}
+
+		wait := backoff
+		if retryable.retryAfter > 0 {
+			wait = retryable.retryAfter
+		} else {
+			// Add up to 20% jitter so that many clients do not retry at the same time
+			wait += time.Duration(rand.Int63n(int64(wait)/5 + 1))
+		}
+		if wait > b.retry.MaxBackoff {
+			wait = b.retry.MaxBackoff
+		}
+
+		// Once the batcher is closing the batch is retried one last time without waiting, so that closing is not
+		// delayed by the backoff
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-b.closing:
+			timer.Stop()
+		}
+		closing = b.isClosing()
+
+		backoff *= 2
+		if backoff > b.retry.MaxBackoff {
+			backoff = b.retry.MaxBackoff
+		}
+	}
+}
+
+// safeSend sends the batch, returning an error if sending panics so that a bad event cannot stop the batcher
+func (b *tBatcher) safeSend(batch []Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return b.send(batch)
+}
+
+// drop counts the batch as dropped after it could not be sent
+func (b *tBatcher) drop(batch []Event, err error) {
+	atomic.AddUint64(&b.dropped, uint64(len(batch)))
+	fmt.Fprintf(os.Stderr, "logtic: error sending %d events to %s: %s\n", len(batch), b.name, err.Error())
+}
+
+// flush waits for all pending events to be sent
+func (b *tBatcher) flush() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for len(b.events) > 0 || b.busy {
+		b.signal()
+		b.idle.Wait()
+	}
+}
+
+// close sends all pending events and stops the batcher. Once the batcher is closing, batches that fail are retried
+// only once and without waiting.
+func (b *tBatcher) close() {
+	b.lock.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.closing)
+	}
+	b.notFull.Broadcast()
+	b.lock.Unlock()
+	b.signal()
+	<-b.done
+}
+
+func (b *tBatcher) isClosing() bool {
+	select {
+	case <-b.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// droppedEvents returns the number of events that were dropped because the queue was full or they could not be sent
+func (b *tBatcher) droppedEvents() uint64 {
+	return atomic.LoadUint64(&b.dropped)
+}
+
+func (b *tBatcher) addDropped(n int) {
+	atomic.AddUint64(&b.dropped, uint64(n))
+}
+
+// postBatch sends a POST request with the given body and returns an error if the request was not successful. Errors
+// that should be retried are returned as a *tRetryableError.
+func postBatch(client *http.Client, url string, headers map[string]string,
+	body []byte) (*http.Response, []byte, error) {
+	if client == nil {
+		client = defaultBatchClient
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, nil, &tRetryableError{err: err}
+	}
+	defer response.Body.Close()
+	// Limit how much of the response is kept, it's only used for error messages and partial failures
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, 4*1024*1024))
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, responseBody, nil
+	}
+
+	err = fmt.Errorf("unexpected HTTP status %s: %s", response.Status, bytes.TrimSpace(truncateBytes(responseBody, 512)))
+	switch response.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		retryAfter := parseRetryAfter(response.Header.Get("Retry-After"))
+		return response, responseBody, &tRetryableError{err: err, retryAfter: retryAfter}
+	}
+	if response.StatusCode >= 500 {
+		return response, responseBody, &tRetryableError{err: err}
+	}
+	return response, responseBody, err
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of seconds or an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func truncateBytes(b []byte, length int) []byte {
+	if len(b) > length {
+		return b[:length]
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+package logtic
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Caller describes the location in code where an event was written
+type Caller struct {
+	// The full path of the source file
+	File string
+	// The line number in the source file
+	Line int
+	// The fully qualified name of the function. Only set if LoggerOptions.CallerFunction is enabled.
+	Function string
+}
+
+// String returns the name of the source file and its parent directory and the line number, for example
+// "server/handler.go:42", followed by the short function name if set.
+func (c Caller) String() string {
+	if c.Function == "" {
+		return c.location()
+	}
+	return c.location() + " " + c.ShortFunction()
+}
+
+func (c Caller) location() string {
+	return shortFilePath(c.File) + ":" + strconv.Itoa(c.Line)
+}
+
+// ShortFunction returns the name of the function without the package path, for example "main.(*Server).handle"
+func (c Caller) ShortFunction() string {
+	return c.Function[strings.LastIndex(c.Function, "/")+1:]
+}
+
+func shortFilePath(file string) string {
+	i := strings.LastIndex(file, "/")
+	if i <= 0 {
+		return file
+	}
+	if j := strings.LastIndex(file[:i], "/"); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
+
+// logticPackage is the import path of this package
+var logticPackage = reflect.TypeOf(Logger{}).PkgPath()
+
+// callerSkipPackages are packages that forward events to logtic, whose frames are skipped when finding the caller
+var callerSkipPackages = map[string]bool{
+	logticPackage: true,
+	"log":         true,
+	"log/slog":    true,
+}
+
+// caller returns the first frame in the call stack outside of logtic and the standard logging packages
+func (s *Source) caller() *Caller {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !callerSkipPackages[functionPackage(frame.Function)] {
+			caller := &Caller{
+				File: frame.File,
+				Line: frame.Line,
+			}
+			if s.instance.Options.CallerFunction {
+				caller.Function = frame.Function
+			}
+			return caller
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// functionPackage returns the import path of the package of the fully qualified function name
+func functionPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
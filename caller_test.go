@@ -0,0 +1,101 @@
+package logtic_test
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestCaller(t *testing.T) {
+	Setup()
+
+	b := &bytes.Buffer{}
+	logtic.Log.Stdout = b
+	logtic.Log.Stderr = b
+	logtic.Log.Color = nil
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Options.Caller = true
+	logtic.Log.Options.CallerFunction = true
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	source := logtic.Log.Connect("test")
+
+	check := func(description string, write func()) {
+		_, file, line, _ := runtime.Caller(1)
+		sink.events = nil
+		write()
+		if len(sink.events) != 1 {
+			t.Fatalf("%s: unexpected number of events %d", description, len(sink.events))
+		}
+		caller := sink.events[0].Caller
+		if caller == nil {
+			t.Errorf("%s: caller not captured", description)
+			return
+		}
+		if caller.File != file || caller.Line != line {
+			t.Errorf("%s: unexpected caller. Expected %s:%d got %s:%d", description, file, line, caller.File, caller.Line)
+		}
+		if !strings.Contains(caller.Function, "logtic_test.TestCaller.func") {
+			t.Errorf("%s: unexpected function '%s'", description, caller.Function)
+		}
+	}
+
+	check("Info", func() { source.Info("message") })
+	check("Write", func() { source.Write(logtic.LevelInfo, "message") })
+	check("PInfo", func() { source.PInfo("event", map[string]any{"key": "value"}) })
+	check("PWrite", func() { source.PWrite(logtic.LevelInfo, "event", map[string]any{"key": "value"}) })
+	check("With", func() { source.With(map[string]any{"key": "value"}).Warn("message") })
+	check("GoLogger", func() { source.GoLogger(logtic.LevelInfo).Printf("message %d", 1) })
+	check("Panic", func() { defer recoverPanic(); source.Panic("message") })
+
+	if !strings.Contains(b.String(), "caller_test.go:") {
+		t.Errorf("Caller not included in console output: '%s'", b.String())
+	}
+	if !strings.Contains(b.String(), " logtic_test.TestCaller.func") {
+		t.Errorf("Function not included in console output: '%s'", b.String())
+	}
+	if !strings.Contains(b.String(), "] message 1\n") {
+		t.Errorf("Unexpected go logger message in console output: '%s'", b.String())
+	}
+
+	logtic.Log.Options.CallerLevel = logtic.LevelWarn
+	sink.events = nil
+	source.Info("message")
+	source.Warn("message")
+	if len(sink.events) != 2 || sink.events[0].Caller != nil || sink.events[1].Caller == nil {
+		t.Errorf("Caller level not respected")
+	}
+
+	logtic.Log.Options.Caller = false
+	sink.events = nil
+	source.Error("message")
+	if len(sink.events) != 1 || sink.events[0].Caller != nil {
+		t.Errorf("Caller captured when disabled")
+	}
+}
+
+func recoverPanic() {
+	recover()
+}
+
+func TestCallerString(t *testing.T) {
+	caller := logtic.Caller{
+		File:     "/home/user/src/server/handler.go",
+		Line:     42,
+		Function: "example.com/app/server.(*Server).handle",
+	}
+	if caller.String() != "server/handler.go:42 server.(*Server).handle" {
+		t.Errorf("Unexpected caller string '%s'", caller.String())
+	}
+	caller.Function = ""
+	if caller.String() != "server/handler.go:42" {
+		t.Errorf("Unexpected caller string '%s'", caller.String())
+	}
+}
@@ -3,6 +3,9 @@ package logtic
 // IColor describes an interface for applying color to a message. By default, logtic only supports ANSI color codes.
 // On platforms where these codes don't work - such as the legacy Console Host on Windows, you may implement your own
 // color interface that applies colors as need for your platform.
+//
+// The notice and critical levels use cyan and magenta if the color interface also implements IColorExtended, otherwise
+// they use blue and red.
 type IColor interface {
 	// Apply a bright black (gray) color to m
 	HiBlack(m string) string
@@ -14,12 +17,23 @@ type IColor interface {
 	Red(m string) string
 }
 
+// IColorExtended describes the additional colors used for the notice and critical levels. Implement it along with
+// IColor to apply these colors, color interfaces that don't implement it use blue and red instead.
+type IColorExtended interface {
+	// Apply a cyan color to m
+	Cyan(m string) string
+	// Apply a magenta color to m
+	Magenta(m string) string
+}
+
 const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorYellow = "\033[33m"
-	colorBlue   = "\033[34m"
-	colorGray   = "\033[90m"
+	colorReset   = "\033[0m"
+	colorRed     = "\033[31m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorCyan    = "\033[36m"
+	colorMagenta = "\033[35m"
+	colorGray    = "\033[90m"
 )
 
 type tDefaultColor struct{}
@@ -30,41 +44,69 @@ func (*tDefaultColor) HiBlack(m string) string {
 func (*tDefaultColor) Blue(m string) string {
 	return colorBlue + m + colorReset
 }
+func (*tDefaultColor) Cyan(m string) string {
+	return colorCyan + m + colorReset
+}
 func (*tDefaultColor) Yellow(m string) string {
 	return colorYellow + m + colorReset
 }
 func (*tDefaultColor) Red(m string) string {
 	return colorRed + m + colorReset
 }
+func (*tDefaultColor) Magenta(m string) string {
+	return colorMagenta + m + colorReset
+}
+
+func (l *Logger) colorHiBlack(m string) string {
+	if l.Color == nil {
+		return m
+	}
+
+	return l.Color.HiBlack(m)
+}
+
+func (l *Logger) colorBlue(m string) string {
+	if l.Color == nil {
+		return m
+	}
+
+	return l.Color.Blue(m)
+}
 
-func (s *Source) colorHiBlack(m string) string {
-	if s.instance.Color == nil {
+func (l *Logger) colorCyan(m string) string {
+	if l.Color == nil {
 		return m
 	}
 
-	return s.instance.Color.HiBlack(m)
+	if extended, ok := l.Color.(IColorExtended); ok {
+		return extended.Cyan(m)
+	}
+	return l.Color.Blue(m)
 }
 
-func (s *Source) colorBlue(m string) string {
-	if s.instance.Color == nil {
+func (l *Logger) colorYellow(m string) string {
+	if l.Color == nil {
 		return m
 	}
 
-	return s.instance.Color.Blue(m)
+	return l.Color.Yellow(m)
 }
 
-func (s *Source) colorYellow(m string) string {
-	if s.instance.Color == nil {
+func (l *Logger) colorRed(m string) string {
+	if l.Color == nil {
 		return m
 	}
 
-	return s.instance.Color.Yellow(m)
+	return l.Color.Red(m)
 }
 
-func (s *Source) colorRed(m string) string {
-	if s.instance.Color == nil {
+func (l *Logger) colorMagenta(m string) string {
+	if l.Color == nil {
 		return m
 	}
 
-	return s.instance.Color.Red(m)
+	if extended, ok := l.Color.(IColorExtended); ok {
+		return extended.Magenta(m)
+	}
+	return l.Color.Red(m)
 }
@@ -53,3 +53,88 @@ func TestNoColor(t *testing.T) {
 		t.Fatalf("Unexpected output.\nExpected:\n\t%x\nGot:\n\t%x", expected, buf)
 	}
 }
+
+func TestColorAdditionalLevels(t *testing.T) {
+	Setup()
+
+	b := &bytes.Buffer{}
+	logtic.Log.Stdout = b
+	logtic.Log.Stderr = b
+
+	logtic.Log.Level = logtic.LevelTrace
+	logtic.Log.Open()
+
+	source := logtic.Log.Connect("example")
+	source.Trace("Trace")
+	source.Notice("Notice")
+	source.Critical("Critical")
+
+	Setup()
+
+	expected := "\x1b[90m[TRACE][example]\x1b[0m Trace\n\x1b[36m[NOTICE][example]\x1b[0m Notice\n\x1b[35m[CRITICAL][example]\x1b[0m Critical\n"
+	if b.String() != expected {
+		t.Fatalf("Unexpected output.\nExpected:\n\t%q\nGot:\n\t%q", expected, b.String())
+	}
+}
+
+// basicColor implements only the colors required by IColor
+type basicColor struct{}
+
+func (basicColor) HiBlack(m string) string { return "gray(" + m + ")" }
+func (basicColor) Blue(m string) string    { return "blue(" + m + ")" }
+func (basicColor) Yellow(m string) string  { return "yellow(" + m + ")" }
+func (basicColor) Red(m string) string     { return "red(" + m + ")" }
+
+func TestColorFallback(t *testing.T) {
+	Setup()
+
+	b := &bytes.Buffer{}
+	logtic.Log.Stdout = b
+	logtic.Log.Stderr = b
+
+	logtic.Log.Level = logtic.LevelTrace
+	logtic.Log.Color = basicColor{}
+	logtic.Log.Open()
+
+	source := logtic.Log.Connect("example")
+	source.Notice("Notice")
+	source.Critical("Critical")
+
+	Setup()
+
+	expected := "blue([NOTICE][example]) Notice\nred([CRITICAL][example]) Critical\n"
+	if b.String() != expected {
+		t.Fatalf("Unexpected output.\nExpected:\n\t%q\nGot:\n\t%q", expected, b.String())
+	}
+}
+
+// extendedColor implements IColor and IColorExtended
+type extendedColor struct {
+	basicColor
+}
+
+func (extendedColor) Cyan(m string) string    { return "cyan(" + m + ")" }
+func (extendedColor) Magenta(m string) string { return "magenta(" + m + ")" }
+
+func TestColorExtended(t *testing.T) {
+	Setup()
+
+	b := &bytes.Buffer{}
+	logtic.Log.Stdout = b
+	logtic.Log.Stderr = b
+
+	logtic.Log.Level = logtic.LevelTrace
+	logtic.Log.Color = extendedColor{}
+	logtic.Log.Open()
+
+	source := logtic.Log.Connect("example")
+	source.Notice("Notice")
+	source.Critical("Critical")
+
+	Setup()
+
+	expected := "cyan([NOTICE][example]) Notice\nmagenta([CRITICAL][example]) Critical\n"
+	if b.String() != expected {
+		t.Fatalf("Unexpected output.\nExpected:\n\t%q\nGot:\n\t%q", expected, b.String())
+	}
+}
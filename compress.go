@@ -0,0 +1,92 @@
+package logtic
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Compressor describes an interface for compressing rotated log files. Logtic includes a gzip compressor, other
+// formats (such as zstd) can be used by implementing this interface.
+type Compressor interface {
+	// Extension returns the file extension added to compressed files, including the leading dot. For example: ".gz"
+	Extension() string
+	// Compress reads all data from r and writes it compressed to w
+	Compress(w io.Writer, r io.Reader) error
+}
+
+// GzipCompressor compresses rotated log files using gzip
+type GzipCompressor struct {
+	// Level is the gzip compression level. Defaults to gzip.DefaultCompression.
+	Level int
+}
+
+// Extension returns ".gz"
+func (*GzipCompressor) Extension() string {
+	return ".gz"
+}
+
+// Compress writes the data from r compressed with gzip to w
+func (c *GzipCompressor) Compress(w io.Writer, r io.Reader) error {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	gw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(gw, r); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// compressRotatedFile will compress the file at filePath in the background, removing the original once complete.
+func (l *Logger) compressRotatedFile(filePath string, compressor Compressor) {
+	fileMode := l.FileMode
+	l.compressing.Add(1)
+	go func() {
+		defer l.compressing.Done()
+		if err := compressFile(filePath, filePath+compressor.Extension(), fileMode, compressor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error compressing rotated log file '%s': %s", filePath, err.Error())
+		}
+	}()
+}
+
+func compressFile(inPath, outPath string, fileMode os.FileMode, compressor Compressor) error {
+	in, err := os.Open(inPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// Compress to a temporary file first so that a partially compressed file never has the final name
+	tmpPath := filepath.Join(filepath.Dir(outPath), "."+filepath.Base(outPath)+".tmp")
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	if err := compressor.Compress(out, in); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Remove(inPath)
+}
@@ -0,0 +1,67 @@
+package logtic_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestRotateCompress(t *testing.T) {
+	Setup()
+
+	dir := t.TempDir()
+
+	logtic.Log.FilePath = path.Join(dir, "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Rotation.Compressor = &logtic.GzipCompressor{}
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	s := logtic.Log.Connect("Test")
+	s.Debug("First file")
+	if err := logtic.Log.RotateDate(); err != nil {
+		t.Fatalf("Error rotating log file: %s", err.Error())
+	}
+	s.Debug("Second file")
+	if err := logtic.Log.RotateDate(); err != nil {
+		t.Fatalf("Error rotating log file: %s", err.Error())
+	}
+	s.Debug("Third file")
+	logtic.Log.Close()
+
+	date := time.Now().Format("2006-01-02")
+	check := func(name, expected string) {
+		rotatedPath := path.Join(dir, name)
+		if _, err := os.Stat(strings.TrimSuffix(rotatedPath, ".gz")); err == nil {
+			t.Errorf("Uncompressed rotated log file was not removed: '%s'", name)
+		}
+
+		f, err := os.Open(rotatedPath)
+		if err != nil {
+			t.Fatalf("Expected compressed log file not found: '%s'", name)
+		}
+		defer f.Close()
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("Error reading compressed log file '%s': %s", name, err.Error())
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Error reading compressed log file '%s': %s", name, err.Error())
+		}
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Compressed log file '%s' does not contain expected data: '%s'", name, data)
+		}
+	}
+
+	check("app.log."+date+".gz", "First file")
+	check("app.log."+date+"-1.gz", "Second file")
+}
@@ -0,0 +1,188 @@
+package logtic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config describes the configuration of a logging instance. Config can be loaded from a JSON file with LoadConfig or
+// from environment variables with ConfigFromEnvironment. Only fields that are set are applied to a logging instance,
+// so multiple configurations can be layered by applying them in order. For example:
+//
+//	{
+//	    "file_path": "/var/log/app.log",
+//	    "level": "info",
+//	    "color": false,
+//	    "format": "json",
+//	    "source_levels": {
+//	        "db": "debug",
+//	        "http": "warn"
+//	    }
+//	}
+type Config struct {
+	// The path to the log file
+	FilePath string `json:"file_path,omitempty"`
+	// The minimum level of events captured
+	Level *LogLevel `json:"level,omitempty"`
+	// The file mode (permissions) of the log file as an octal string, such as "0640"
+	FileMode string `json:"file_mode,omitempty"`
+	// Should color be applied to console output
+	Color *bool `json:"color,omitempty"`
+	// Should control characters be escaped
+	EscapeCharacters *bool `json:"escape_characters,omitempty"`
+	// The format of the log file, either "text" or "json"
+	Format string `json:"format,omitempty"`
+	// The minimum level of events captured for specific sources, by source name or pattern. See Logger.SetSourceLevel.
+	SourceLevels map[string]LogLevel `json:"source_levels,omitempty"`
+}
+
+// LoadConfig will read the JSON configuration file at filePath
+func LoadConfig(filePath string) (Config, error) {
+	config := Config{}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid logtic config '%s': %s", filePath, err.Error())
+	}
+	return config, nil
+}
+
+// ConfigFromEnvironment will read the configuration from environment variables. Variables that are not set or are
+// empty are ignored. The supported variables are:
+//
+//	LOGTIC_FILE           the path to the log file
+//	LOGTIC_LEVEL          the minimum level, such as "debug"
+//	LOGTIC_FILE_MODE      the file mode as an octal string, such as "0640"
+//	LOGTIC_COLOR          if color is applied to console output, such as "true" or "false"
+//	LOGTIC_ESCAPE         if control characters are escaped, such as "true" or "false"
+//	LOGTIC_FORMAT         the format of the log file, either "text" or "json"
+//	LOGTIC_SOURCE_LEVELS  levels for specific sources, such as "db=debug,http=warn"
+func ConfigFromEnvironment() (Config, error) {
+	config := Config{
+		FilePath: os.Getenv("LOGTIC_FILE"),
+		FileMode: os.Getenv("LOGTIC_FILE_MODE"),
+		Format:   os.Getenv("LOGTIC_FORMAT"),
+	}
+
+	if v := os.Getenv("LOGTIC_LEVEL"); v != "" {
+		level, err := ParseLevel(v)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOGTIC_LEVEL: %s", err.Error())
+		}
+		config.Level = &level
+	}
+	if v := os.Getenv("LOGTIC_COLOR"); v != "" {
+		color, err := strconv.ParseBool(v)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOGTIC_COLOR: %s", err.Error())
+		}
+		config.Color = &color
+	}
+	if v := os.Getenv("LOGTIC_ESCAPE"); v != "" {
+		escape, err := strconv.ParseBool(v)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOGTIC_ESCAPE: %s", err.Error())
+		}
+		config.EscapeCharacters = &escape
+	}
+	if v := os.Getenv("LOGTIC_SOURCE_LEVELS"); v != "" {
+		sourceLevels, err := ParseSourceLevels(v)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOGTIC_SOURCE_LEVELS: %s", err.Error())
+		}
+		config.SourceLevels = sourceLevels
+	}
+
+	return config, nil
+}
+
+// ParseSourceLevels parses a comma separated list of source names or patterns and levels, such as
+// "db=debug,http.*=warn"
+func ParseSourceLevels(s string) (map[string]LogLevel, error) {
+	sourceLevels := map[string]LogLevel{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, levelName, found := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid source level '%s', expected name=level", pair)
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, err
+		}
+		sourceLevels[name] = level
+	}
+	return sourceLevels, nil
+}
+
+// NewFromConfig will create a new logging instance with the given configuration applied to the default options
+func NewFromConfig(config Config) (*Logger, error) {
+	l := New()
+	if err := l.ApplyConfig(config); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ApplyConfig will apply the given configuration to this logging instance. Only fields that are set in the
+// configuration are changed. Changes to the file path and file mode take effect the next time the instance is opened.
+// If the configuration is invalid then no changes are made.
+func (l *Logger) ApplyConfig(config Config) error {
+	var fileMode os.FileMode
+	if config.FileMode != "" {
+		mode, err := strconv.ParseUint(config.FileMode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid file mode '%s'", config.FileMode)
+		}
+		fileMode = os.FileMode(mode)
+	}
+
+	var formatter Formatter
+	switch strings.ToLower(config.Format) {
+	case "":
+		break
+	case "text":
+		formatter = &TextFormatter{}
+	case "json":
+		formatter = &JSONFormatter{}
+	default:
+		return fmt.Errorf("unknown log format '%s'", config.Format)
+	}
+
+	if config.FilePath != "" {
+		l.FilePath = config.FilePath
+	}
+	if config.Level != nil {
+		l.Level = *config.Level
+	}
+	if config.FileMode != "" {
+		l.FileMode = fileMode
+	}
+	if config.Color != nil {
+		if *config.Color {
+			l.Color = &tDefaultColor{}
+		} else {
+			l.Color = nil
+		}
+	}
+	if config.EscapeCharacters != nil {
+		l.Options.EscapeCharacters = *config.EscapeCharacters
+	}
+	if formatter != nil {
+		l.Formatter = formatter
+	}
+	for name, level := range config.SourceLevels {
+		l.SetSourceLevel(name, level)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package logtic_test
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := path.Join(dir, "logtic.json")
+	logPath := path.Join(dir, "logtic.log")
+
+	if err := os.WriteFile(configPath, []byte(`{
+		"file_path": "`+logPath+`",
+		"level": "warn",
+		"file_mode": "0600",
+		"color": false,
+		"format": "json",
+		"source_levels": {"db": "debug"}
+	}`), 0644); err != nil {
+		t.Fatalf("Error writing config: %s", err.Error())
+	}
+
+	config, err := logtic.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Error loading config: %s", err.Error())
+	}
+	log, err := logtic.NewFromConfig(config)
+	if err != nil {
+		t.Fatalf("Error applying config: %s", err.Error())
+	}
+	SetStdOut(log)
+
+	if log.FilePath != logPath {
+		t.Errorf("Unexpected file path: '%s'", log.FilePath)
+	}
+	if log.Level != logtic.LevelWarn {
+		t.Errorf("Unexpected level: %s", log.Level)
+	}
+	if log.FileMode != 0600 {
+		t.Errorf("Unexpected file mode: %o", log.FileMode)
+	}
+	if log.Color != nil {
+		t.Errorf("Color not disabled")
+	}
+	if !log.Options.EscapeCharacters {
+		t.Errorf("Unset option was changed")
+	}
+	if _, ok := log.Formatter.(*logtic.JSONFormatter); !ok {
+		t.Errorf("Unexpected formatter: %T", log.Formatter)
+	}
+
+	if err := log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	log.Connect("db").Debug("db message")
+	log.Connect("http").Debug("http message")
+	log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if !regexp.MustCompile(`"source":"db","message":"db message"`).Match(data) {
+		t.Errorf("Log file does not contain expected event for source with level")
+	}
+	if regexp.MustCompile(`http message`).Match(data) {
+		t.Errorf("Log file contains event that should not exist")
+	}
+}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	t.Setenv("LOGTIC_FILE", "/tmp/app.log")
+	t.Setenv("LOGTIC_LEVEL", "debug")
+	t.Setenv("LOGTIC_COLOR", "false")
+	t.Setenv("LOGTIC_SOURCE_LEVELS", "db=trace, http=warn")
+
+	config, err := logtic.ConfigFromEnvironment()
+	if err != nil {
+		t.Fatalf("Error loading config: %s", err.Error())
+	}
+
+	if config.FilePath != "/tmp/app.log" {
+		t.Errorf("Unexpected file path: '%s'", config.FilePath)
+	}
+	if config.Level == nil || *config.Level != logtic.LevelDebug {
+		t.Errorf("Unexpected level: %v", config.Level)
+	}
+	if config.Color == nil || *config.Color {
+		t.Errorf("Unexpected color: %v", config.Color)
+	}
+	if config.EscapeCharacters != nil {
+		t.Errorf("Unset variable was included in config")
+	}
+	if len(config.SourceLevels) != 2 || config.SourceLevels["db"] != logtic.LevelTrace || config.SourceLevels["http"] != logtic.LevelWarn {
+		t.Errorf("Unexpected source levels: %v", config.SourceLevels)
+	}
+
+	t.Setenv("LOGTIC_LEVEL", "loud")
+	if _, err := logtic.ConfigFromEnvironment(); err == nil {
+		t.Errorf("No error seen when one expected for invalid level")
+	}
+}
+
+func TestParseSourceLevels(t *testing.T) {
+	for _, in := range []string{"db", "=debug", "db=loud"} {
+		if _, err := logtic.ParseSourceLevels(in); err == nil {
+			t.Errorf("No error seen when one expected for '%s'", in)
+		}
+	}
+}
+
+func TestApplyConfigInvalid(t *testing.T) {
+	Setup()
+
+	level := logtic.LevelDebug
+	if err := logtic.Log.ApplyConfig(logtic.Config{Level: &level, Format: "xml"}); err == nil {
+		t.Errorf("No error seen when one expected for invalid format")
+	}
+	if logtic.Log.Level == logtic.LevelDebug {
+		t.Errorf("Invalid config was partially applied")
+	}
+}
+
+func TestConfigFileInvalidLevel(t *testing.T) {
+	configPath := path.Join(t.TempDir(), "logtic.json")
+	for _, config := range []string{`{"level": 42}`, `{"source_levels": {"db": -2}}`} {
+		if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+			t.Fatalf("Error writing config: %s", err.Error())
+		}
+		if _, err := logtic.LoadConfig(configPath); err == nil {
+			t.Errorf("No error seen when one expected for config %s", config)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package logtic
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor describes an interface for contributing parameters to events from a context. Extractors are
+// registered with RegisterContextExtractor and are called for every event written with a context, such as by
+// Source.InfoCtx.
+type ContextExtractor interface {
+	// ContextParameters returns the parameters to include in events written with the given context, or nil.
+	ContextParameters(ctx context.Context) map[string]any
+}
+
+// ContextExtractorFunc is a function that can be used as a ContextExtractor
+type ContextExtractorFunc func(ctx context.Context) map[string]any
+
+// ContextParameters calls f(ctx)
+func (f ContextExtractorFunc) ContextParameters(ctx context.Context) map[string]any {
+	return f(ctx)
+}
+
+type tContextKey struct{}
+
+var (
+	contextExtractors    []ContextExtractor
+	contextExtractorLock sync.RWMutex
+)
+
+// RegisterContextExtractor will register an extractor that contributes parameters from a context to all events
+// written with a context, for all logging instances. Parameters added with NewContext take precedence over parameters
+// from extractors.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorLock.Lock()
+	defer contextExtractorLock.Unlock()
+	contextExtractors = append(contextExtractors, extractor)
+}
+
+// NewContext returns a copy of ctx carrying the given parameters, which are included in every event written with the
+// returned context, such as by Source.InfoCtx. Any parameters already carried by ctx are kept, with the given
+// parameters taking precedence.
+func NewContext(ctx context.Context, parameters map[string]any) context.Context {
+	existing, _ := ctx.Value(tContextKey{}).(map[string]any)
+	merged := make(map[string]any, len(existing)+len(parameters))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range parameters {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, tContextKey{}, merged)
+}
+
+// FromContext returns the parameters carried by ctx that were added with NewContext, or nil. The returned map must not
+// be modified.
+func FromContext(ctx context.Context) map[string]any {
+	parameters, _ := ctx.Value(tContextKey{}).(map[string]any)
+	return parameters
+}
+
+// contextParameters returns all parameters for ctx from extractors and NewContext
+func contextParameters(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+
+	var parameters map[string]any
+	contextExtractorLock.RLock()
+	for _, extractor := range contextExtractors {
+		extracted := extractor.ContextParameters(ctx)
+		if len(extracted) == 0 {
+			continue
+		}
+		if parameters == nil {
+			parameters = map[string]any{}
+		}
+		for k, v := range extracted {
+			parameters[k] = v
+		}
+	}
+	contextExtractorLock.RUnlock()
+
+	fromContext := FromContext(ctx)
+	if parameters == nil {
+		return fromContext
+	}
+	for k, v := range fromContext {
+		parameters[k] = v
+	}
+	return parameters
+}
+
+// withContext returns a source with the parameters for ctx bound to it
+func (s *Source) withContext(ctx context.Context) *Source {
+	parameters := contextParameters(ctx)
+	if len(parameters) == 0 {
+		return s
+	}
+	return s.With(parameters)
+}
+
+func (s *Source) logCtx(ctx context.Context, level LogLevel, format string, a ...interface{}) {
+	defer s.panicRecover()
+	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(level) {
+		return
+	}
+	source := s.withContext(ctx)
+	source.write(withTrace(ctx, source.newEvent(level, format, a...)))
+}
+
+func (s *Source) plogCtx(ctx context.Context, level LogLevel, event string, parameters map[string]any) {
+	defer s.panicRecover()
+	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(level) {
+		return
+	}
+	source := s.withContext(ctx)
+	source.write(withTrace(ctx, source.newParameterizedEvent(level, event, parameters)))
+}
+
+// withTrace returns the event with the trace context for ctx, if any
+func withTrace(ctx context.Context, event Event) Event {
+	if trace, ok := TraceContextFromContext(ctx); ok {
+		event.Trace = &trace
+	}
+	return event
+}
+
+// TraceCtx will log a trace formatted message, including any parameters carried by ctx.
+func (s *Source) TraceCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelTrace, format, a...)
+}
+
+// DebugCtx will log a debug formatted message, including any parameters carried by ctx.
+func (s *Source) DebugCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelDebug, format, a...)
+}
+
+// InfoCtx will log an informational formatted message, including any parameters carried by ctx.
+func (s *Source) InfoCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelInfo, format, a...)
+}
+
+// NoticeCtx will log a notice formatted message, including any parameters carried by ctx.
+func (s *Source) NoticeCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelNotice, format, a...)
+}
+
+// WarnCtx will log a warning formatted message, including any parameters carried by ctx.
+func (s *Source) WarnCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelWarn, format, a...)
+}
+
+// ErrorCtx will log an error formatted message, including any parameters carried by ctx. Errors are printed to stderr.
+func (s *Source) ErrorCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelError, format, a...)
+}
+
+// CriticalCtx will log a critical formatted message, including any parameters carried by ctx. Critical messages are
+// printed to stderr.
+func (s *Source) CriticalCtx(ctx context.Context, format string, a ...interface{}) {
+	s.logCtx(ctx, LevelCritical, format, a...)
+}
+
+// PTraceCtx will log a trace parameterized message, including any parameters carried by ctx.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PTraceCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelTrace, event, parameters)
+}
+
+// PDebugCtx will log a debug parameterized message, including any parameters carried by ctx.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PDebugCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelDebug, event, parameters)
+}
+
+// PInfoCtx will log an informational parameterized message, including any parameters carried by ctx.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PInfoCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelInfo, event, parameters)
+}
+
+// PNoticeCtx will log a notice parameterized message, including any parameters carried by ctx.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PNoticeCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelNotice, event, parameters)
+}
+
+// PWarnCtx will log a warning parameterized message, including any parameters carried by ctx.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PWarnCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelWarn, event, parameters)
+}
+
+// PErrorCtx will log an error parameterized message, including any parameters carried by ctx. Errors are printed to
+// stderr.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PErrorCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelError, event, parameters)
+}
+
+// PCriticalCtx will log a critical parameterized message, including any parameters carried by ctx. Critical messages
+// are printed to stderr.
+// Parameters of the event take precedence over parameters carried by ctx.
+func (s *Source) PCriticalCtx(ctx context.Context, event string, parameters map[string]any) {
+	s.plogCtx(ctx, LevelCritical, event, parameters)
+}
@@ -0,0 +1,86 @@
+package logtic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type tenantKey struct{}
+
+func TestContext(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	source := logtic.Log.Connect("test").With(map[string]any{"component": "api", "request_id": "bound"})
+
+	ctx := logtic.NewContext(context.Background(), map[string]any{"request_id": "abc"})
+	ctx = logtic.NewContext(ctx, map[string]any{"user": "alice"})
+	if params := logtic.FromContext(ctx); len(params) != 2 || params["request_id"] != "abc" {
+		t.Errorf("Unexpected parameters from context: %v", params)
+	}
+	if logtic.FromContext(context.Background()) != nil {
+		t.Errorf("Unexpected parameters from empty context")
+	}
+
+	source.InfoCtx(ctx, "hello %s", "world")
+	source.PWarnCtx(ctx, "Event", map[string]any{"user": "bob"})
+	source.TraceCtx(ctx, "not captured")
+	source.DebugCtx(context.Background(), "no context")
+
+	expected := []string{
+		"hello world: component='api' request_id='abc' user='alice'",
+		"Event: component='api' request_id='abc' user='bob'",
+		"no context: component='api' request_id='bound'",
+	}
+	if len(sink.events) != len(expected) {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	for i, message := range expected {
+		if sink.events[i].Message != message {
+			t.Errorf("Unexpected message.\nExpected: %s\nGot:      %s", message, sink.events[i].Message)
+		}
+	}
+}
+
+func TestContextExtractor(t *testing.T) {
+	Setup()
+
+	logtic.RegisterContextExtractor(logtic.ContextExtractorFunc(func(ctx context.Context) map[string]any {
+		tenant, ok := ctx.Value(tenantKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return map[string]any{"tenant": tenant, "request_id": "extracted"}
+	}))
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	source := logtic.Log.Connect("test")
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	source.ErrorCtx(ctx, "message")
+	source.PCriticalCtx(logtic.NewContext(ctx, map[string]any{"request_id": "abc"}), "Event", nil)
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	if sink.events[0].Message != "message: request_id='extracted' tenant='acme'" {
+		t.Errorf("Unexpected message '%s'", sink.events[0].Message)
+	}
+	if sink.events[1].Message != "Event: request_id='abc' tenant='acme'" {
+		t.Errorf("Unexpected message '%s'", sink.events[1].Message)
+	}
+}
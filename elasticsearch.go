@@ -0,0 +1,211 @@
+package logtic
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ElasticsearchSink is a sink that indexes events into Elasticsearch or OpenSearch using the bulk API. Events are sent
+// in batches in the background, see BatchOptions and RetryPolicy. If the server cannot keep up and the queue is full,
+// writing events blocks until there is room unless a different overflow policy is set in the batch options.
+//
+// Each event is indexed as a document shaped like the Elastic Common Schema:
+//
+//	{"@timestamp":"2021-03-15T21:43:34.123Z","log.level":"info","log.logger":"Example","message":"Login: user='alice'","event.action":"Login","labels":{"user":"alice"}}
+//
+// Parameters are included as strings in "labels". If captured, the caller is included in "log.origin", the stack in
+// "error.stack_trace", and the trace context in "trace.id" and "span.id".
+//
+// Documents rejected by the server with 429 Too Many Requests or a 5xx status are retried, other rejected documents are
+// dropped.
+type ElasticsearchSink struct {
+	// URL is the URL of the server, such as "http://localhost:9200"
+	URL string
+	// Index is the prefix of the name of the index, followed by a hyphen and the date of the event. Defaults to
+	// "logtic".
+	Index string
+	// IndexDateLayout is the layout of the date in the name of the index, using the UTC date of the event. Defaults to
+	// "2006.01.02", creating a new index every day.
+	IndexDateLayout string
+	// Username and Password are used for HTTP basic authentication, if set
+	Username string
+	Password string
+	// Headers are additional headers included with each request, such as Authorization for an API key
+	Headers map[string]string
+	// Client is the HTTP client used to send requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+	// Batch describes how events are batched
+	Batch BatchOptions
+	// Retry describes how failed requests are retried
+	Retry RetryPolicy
+
+	tBatchSink
+}
+
+// NewElasticsearchSink will create a new Elasticsearch sink that indexes events into daily indexes starting with index
+// on the server at the given URL, such as "http://localhost:9200"
+func NewElasticsearchSink(url string, index string) *ElasticsearchSink {
+	return &ElasticsearchSink{
+		URL:   url,
+		Index: index,
+	}
+}
+
+// WriteEvent adds the event to the next batch to be sent
+func (s *ElasticsearchSink) WriteEvent(event Event) error {
+	s.write(event, func() *tBatcher {
+		return newBatcher("Elasticsearch server "+s.URL, s.Batch, s.Retry, s.send)
+	})
+	return nil
+}
+
+type tElasticsearchDocument struct {
+	Timestamp  string                `json:"@timestamp"`
+	Level      string                `json:"log.level"`
+	Logger     string                `json:"log.logger"`
+	Message    string                `json:"message"`
+	Action     string                `json:"event.action,omitempty"`
+	Labels     map[string]string     `json:"labels,omitempty"`
+	Origin     *tElasticsearchOrigin `json:"log.origin,omitempty"`
+	StackTrace string                `json:"error.stack_trace,omitempty"`
+	TraceID    string                `json:"trace.id,omitempty"`
+	SpanID     string                `json:"span.id,omitempty"`
+}
+
+type tElasticsearchOrigin struct {
+	File struct {
+		Name string `json:"name"`
+		Line int    `json:"line"`
+	} `json:"file"`
+	Function string `json:"function,omitempty"`
+}
+
+type tElasticsearchBulkResponse struct {
+	Errors bool                                  `json:"errors"`
+	Items  []map[string]tElasticsearchBulkResult `json:"items"`
+}
+
+type tElasticsearchBulkResult struct {
+	Status int `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+func elasticsearchDocument(event Event) tElasticsearchDocument {
+	document := tElasticsearchDocument{
+		Timestamp:  event.Time.UTC().Format(time.RFC3339Nano),
+		Level:      strings.ToLower(event.Level.String()),
+		Logger:     event.Source,
+		Message:    event.Message,
+		Action:     event.Event,
+		StackTrace: event.Stack,
+	}
+	if len(event.Parameters) > 0 {
+		document.Labels = make(map[string]string, len(event.Parameters))
+		for k, v := range event.Parameters {
+			document.Labels[k], _ = parameterString(v)
+		}
+	}
+	if event.Caller != nil {
+		document.Origin = &tElasticsearchOrigin{Function: event.Caller.Function}
+		document.Origin.File.Name = event.Caller.File
+		document.Origin.File.Line = event.Caller.Line
+	}
+	if event.Trace != nil {
+		document.TraceID = event.Trace.TraceID
+		document.SpanID = event.Trace.SpanID
+	}
+	return document
+}
+
+func (s *ElasticsearchSink) index(t time.Time) string {
+	index := s.Index
+	if index == "" {
+		index = "logtic"
+	}
+	layout := s.IndexDateLayout
+	if layout == "" {
+		layout = "2006.01.02"
+	}
+	return index + "-" + t.UTC().Format(layout)
+}
+
+func (s *ElasticsearchSink) send(events []Event) error {
+	body := &bytes.Buffer{}
+	encoder := json.NewEncoder(body)
+	for _, event := range events {
+		action := map[string]any{"create": map[string]string{"_index": s.index(event.Time)}}
+		if err := encoder.Encode(action); err != nil {
+			return err
+		}
+		if err := encoder.Encode(elasticsearchDocument(event)); err != nil {
+			return err
+		}
+	}
+
+	headers := map[string]string{}
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	headers["Content-Type"] = "application/x-ndjson"
+	if s.Username != "" || s.Password != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.Username+":"+s.Password))
+	}
+
+	// Only the status and error of each item are needed to handle partial failures
+	url := strings.TrimRight(s.URL, "/") + "/_bulk?filter_path=errors,items.*.status,items.*.error"
+	_, responseBody, err := postBatch(s.Client, url, headers, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	response := tElasticsearchBulkResponse{}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return fmt.Errorf("invalid bulk response: %s", err.Error())
+	}
+	if !response.Errors {
+		return nil
+	}
+	if len(response.Items) != len(events) {
+		return fmt.Errorf("unexpected number of items in bulk response. Expected %d got %d", len(events),
+			len(response.Items))
+	}
+
+	// Retry documents that were rejected because the server is overloaded, and drop all other rejected documents
+	retry := []Event{}
+	var rejected int
+	var reason string
+	for i, item := range response.Items {
+		for _, result := range item {
+			if result.Status < 300 {
+				continue
+			}
+			if result.Status == http.StatusTooManyRequests || result.Status >= 500 {
+				retry = append(retry, events[i])
+				continue
+			}
+			rejected++
+			if reason == "" && result.Error != nil {
+				reason = result.Error.Type + ": " + result.Error.Reason
+			}
+		}
+	}
+	if rejected > 0 {
+		s.lock.Lock()
+		s.batcher.addDropped(rejected)
+		s.lock.Unlock()
+		fmt.Fprintf(os.Stderr, "logtic: %d events rejected by %s: %s\n", rejected, s.URL, reason)
+	}
+	if len(retry) > 0 {
+		return &tRetryableError{err: fmt.Errorf("%d events rejected with a temporary error", len(retry)), events: retry}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package logtic_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+// bulkServer is a fake Elasticsearch server that records indexed documents. Each call to the bulk API responds with
+// the next set of item statuses, or 201 Created for every item. If wait is not nil, requests wait until it is closed.
+type bulkServer struct {
+	server    *httptest.Server
+	lock      sync.Mutex
+	requests  int
+	actions   []map[string]map[string]string
+	documents []map[string]any
+	statuses  [][]int
+}
+
+func newBulkServer(t *testing.T, wait <-chan struct{}, statuses ...[]int) *bulkServer {
+	s := &bulkServer{statuses: statuses}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait != nil {
+			<-wait
+		}
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "elastic" || pass != "changeme" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.requests++
+		var status []int
+		if len(s.statuses) > 0 {
+			status = s.statuses[0]
+			s.statuses = s.statuses[1:]
+		}
+
+		items := []string{}
+		errors := false
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(nil, 1024*1024)
+		for i := 0; scanner.Scan(); i++ {
+			action := map[string]map[string]string{}
+			json.Unmarshal(scanner.Bytes(), &action)
+			scanner.Scan()
+			document := map[string]any{}
+			json.Unmarshal(scanner.Bytes(), &document)
+
+			code := http.StatusCreated
+			if i < len(status) {
+				code = status[i]
+			}
+			if code >= 300 {
+				errors = true
+				items = append(items, fmt.Sprintf(
+					`{"create":{"status":%d,"error":{"type":"test_exception","reason":"status %d"}}}`, code, code))
+				continue
+			}
+			s.actions = append(s.actions, action)
+			s.documents = append(s.documents, document)
+			items = append(items, fmt.Sprintf(`{"create":{"status":%d}}`, code))
+		}
+		fmt.Fprintf(w, `{"errors":%t,"items":[%s]}`, errors, strings.Join(items, ","))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// results returns the number of requests and the indexed actions and documents
+func (s *bulkServer) results() (int, []map[string]map[string]string, []map[string]any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests, s.actions, s.documents
+}
+
+func newTestElasticsearchSink(s *bulkServer) *logtic.ElasticsearchSink {
+	sink := logtic.NewElasticsearchSink(s.server.URL, "app")
+	sink.Username = "elastic"
+	sink.Password = "changeme"
+	sink.Retry = logtic.RetryPolicy{InitialBackoff: time.Millisecond}
+	return sink
+}
+
+func TestElasticsearchSink(t *testing.T) {
+	server := newBulkServer(t, nil)
+
+	sink := newTestElasticsearchSink(server)
+	for _, event := range testOTLPEvents() {
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	requests, actions, documents := server.results()
+	if requests != 1 || len(documents) != 2 {
+		t.Fatalf("Unexpected number of requests or documents: %d %d", requests, len(documents))
+	}
+	if index := actions[0]["create"]["_index"]; index != "app-2023.11.14" {
+		t.Errorf("Unexpected index '%s'", index)
+	}
+
+	document := documents[0]
+	if document["@timestamp"] != "2023-11-14T22:13:20Z" || document["log.level"] != "info" ||
+		document["log.logger"] != "http" || document["message"] != "Request finished" {
+		t.Errorf("Unexpected document: %+v", document)
+	}
+	if _, ok := document["labels"]; ok {
+		t.Errorf("Unexpected labels for event without parameters")
+	}
+
+	document = documents[1]
+	labels := document["labels"].(map[string]any)
+	if document["event.action"] != "Slow query" || labels["duration"] != "1500" || labels["table"] != "users" {
+		t.Errorf("Unexpected document: %+v", document)
+	}
+	if document["trace.id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || document["span.id"] != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected trace context: %+v", document)
+	}
+	origin := document["log.origin"].(map[string]any)
+	if origin["function"] != "example.com/db.Query" || origin["file"].(map[string]any)["line"] != float64(42) {
+		t.Errorf("Unexpected origin: %+v", origin)
+	}
+}
+
+func TestElasticsearchSinkIndexDateLayout(t *testing.T) {
+	server := newBulkServer(t, nil)
+
+	sink := newTestElasticsearchSink(server)
+	sink.Index = ""
+	sink.IndexDateLayout = "2006.01"
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Close()
+
+	_, actions, _ := server.results()
+	if index := actions[0]["create"]["_index"]; index != "logtic-2023.11" {
+		t.Errorf("Unexpected index '%s'", index)
+	}
+}
+
+func TestElasticsearchSinkPartialFailure(t *testing.T) {
+	// The first request rejects the second document permanently and the third temporarily
+	server := newBulkServer(t, nil, []int{201, 400, 429, 201})
+
+	sink := newTestElasticsearchSink(server)
+	for i := 0; i < 4; i++ {
+		event := testOTLPEvents()[0]
+		event.Message = fmt.Sprintf("Event %d", i)
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	requests, _, documents := server.results()
+	if requests != 2 {
+		t.Errorf("Unexpected number of requests. Expected 2 got %d", requests)
+	}
+	messages := []string{}
+	for _, document := range documents {
+		messages = append(messages, document["message"].(string))
+	}
+	if strings.Join(messages, ",") != "Event 0,Event 3,Event 2" {
+		t.Errorf("Unexpected indexed events: %v", messages)
+	}
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestElasticsearchSinkRetryExhausted(t *testing.T) {
+	server := newBulkServer(t, nil, []int{503}, []int{503})
+
+	sink := newTestElasticsearchSink(server)
+	sink.Retry.MaxAttempts = 2
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Close()
+
+	requests, _, documents := server.results()
+	if requests != 2 || len(documents) != 0 {
+		t.Errorf("Unexpected number of requests or documents: %d %d", requests, len(documents))
+	}
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestElasticsearchSinkBackpressure(t *testing.T) {
+	block := make(chan struct{})
+	server := newBulkServer(t, block)
+
+	sink := newTestElasticsearchSink(server)
+	sink.Batch = logtic.BatchOptions{MaxEvents: 1, QueueSize: 1}
+	event := testOTLPEvents()[0]
+
+	// The first event is being sent and the second fills the queue, so the third blocks until the server responds
+	sink.WriteEvent(event)
+	sink.WriteEvent(event)
+	written := make(chan struct{})
+	go func() {
+		sink.WriteEvent(event)
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Errorf("Event written while the queue was full")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(block)
+	<-written
+	sink.Close()
+
+	if _, _, documents := server.results(); len(documents) != 3 {
+		t.Errorf("Unexpected number of documents. Expected 3 got %d", len(documents))
+	}
+	if dropped := sink.DroppedEvents(); dropped != 0 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestElasticsearchDocumentJSON(t *testing.T) {
+	// Parameters that are not strings are formatted the same way as the message
+	server := newBulkServer(t, nil)
+
+	sink := newTestElasticsearchSink(server)
+	sink.WriteEvent(logtic.Event{
+		Time:       time.Unix(0, 0),
+		Level:      logtic.LevelError,
+		Source:     "test",
+		Parameters: map[string]any{"data": []byte{0x01, 0x02}, "ok": true},
+	})
+	sink.Close()
+
+	_, _, documents := server.results()
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(documents[0]["labels"])
+	if labels := strings.TrimSpace(buf.String()); labels != `{"data":"0102","ok":"true"}` {
+		t.Errorf("Unexpected labels %s", labels)
+	}
+}
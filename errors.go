@@ -0,0 +1,128 @@
+package logtic
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrorWithFields describes an error that provides additional fields to include when it is logged. Fields are added
+// to the event as parameters prefixed with the key of the error, for example the field "query" of an error with the
+// key "error" becomes the parameter "error.query".
+type ErrorWithFields interface {
+	error
+	ErrorFields() map[string]any
+}
+
+// maxErrorDepth is the maximum number of wrapped errors that are expanded
+const maxErrorDepth = 32
+
+// plainErrorType is the type of errors created by errors.New, or fmt.Errorf without wrapping another error
+var plainErrorType = fmt.Sprintf("%T", errors.New(""))
+
+// ErrorE will log an error parameterized message for the given error. The error is included in the parameters with
+// the key "error" and is expanded the same as any other error parameter, see LoggerOptions.ExpandErrors.
+func (s *Source) ErrorE(err error, event string, parameters map[string]any) {
+	withError := make(map[string]any, len(parameters)+1)
+	for k, v := range parameters {
+		withError[k] = v
+	}
+	withError["error"] = err
+	s.plog(LevelError, event, withError)
+}
+
+// expandErrors returns the parameters with any error values expanded, if enabled. The given parameters are never
+// modified.
+func (s *Source) expandErrors(parameters map[string]any) map[string]any {
+	if s == nil || s.instance == nil || !s.instance.Options.ExpandErrors {
+		return parameters
+	}
+
+	var expanded map[string]any
+	for k, v := range parameters {
+		err, isError := v.(error)
+		if !isError || isNilValue(v) {
+			continue
+		}
+		if expanded == nil {
+			expanded = make(map[string]any, len(parameters)+4)
+			for k, v := range parameters {
+				expanded[k] = v
+			}
+		}
+		expandError(expanded, k, err, 0)
+	}
+	if expanded == nil {
+		return parameters
+	}
+	return expanded
+}
+
+// expandError adds parameters describing err to parameters:
+//
+//	key        the error itself
+//	key.type   the concrete type of the error, unless it is a plain error created by errors.New or fmt.Errorf
+//	key.chain  the types of each error in the chain of wrapped errors, if the error wraps another
+//	key.cause  the message of the last error in the chain of wrapped errors, if the error wraps another
+//	key.N      each error joined by the error, expanded the same way
+//	key.field  any fields from errors in the chain that implement ErrorWithFields
+func expandError(parameters map[string]any, key string, err error, depth int) {
+	chain := []string{fmt.Sprintf("%T", err)}
+	fields := map[string]any{}
+	addFields := func(err error) {
+		if withFields, ok := err.(ErrorWithFields); ok {
+			for k, v := range withFields.ErrorFields() {
+				if _, exists := fields[k]; !exists {
+					fields[k] = v
+				}
+			}
+		}
+	}
+
+	cause := err
+	addFields(cause)
+	for depth < maxErrorDepth {
+		if joined, ok := cause.(interface{ Unwrap() []error }); ok {
+			for i, e := range joined.Unwrap() {
+				if e != nil {
+					expandError(parameters, key+"."+strconv.Itoa(i), e, depth+1)
+				}
+			}
+			break
+		}
+		next := errors.Unwrap(cause)
+		if next == nil {
+			break
+		}
+		cause = next
+		depth++
+		chain = append(chain, fmt.Sprintf("%T", cause))
+		addFields(cause)
+	}
+
+	for k, v := range fields {
+		parameters[key+"."+k] = v
+	}
+	parameters[key] = err
+	// The type of plain errors is left out as it adds nothing to the message
+	if chain[0] != plainErrorType {
+		parameters[key+".type"] = chain[0]
+	}
+	if len(chain) > 1 {
+		parameters[key+".chain"] = strings.Join(chain, " > ")
+		if _, joined := cause.(interface{ Unwrap() []error }); !joined {
+			parameters[key+".cause"] = cause.Error()
+		}
+	}
+}
+
+func isNilValue(v any) bool {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package logtic_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type queryError struct {
+	query string
+	err   error
+}
+
+func (e *queryError) Error() string {
+	return "query failed: " + e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+func (e *queryError) ErrorFields() map[string]any {
+	return map[string]any{"query": e.query}
+}
+
+type multiError []error
+
+func (e multiError) Error() string {
+	return fmt.Sprintf("%s; %s", e[0], e[1])
+}
+
+func (e multiError) Unwrap() []error {
+	return e
+}
+
+func TestErrorParameters(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	source := logtic.Log.Connect("test")
+
+	cause := &fs.PathError{Op: "open", Path: "/db", Err: fs.ErrNotExist}
+	err := fmt.Errorf("loading users: %w", &queryError{query: "SELECT 1", err: cause})
+	source.ErrorE(err, "Error loading users", map[string]any{"user": 1})
+
+	if len(sink.events) != 1 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	event := sink.events[0]
+	if event.Level != logtic.LevelError || event.Event != "Error loading users" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	expected := "Error loading users: error='loading users: query failed: open /db: file does not exist' " +
+		"error.cause='file does not exist' " +
+		"error.chain='*fmt.wrapError > *logtic_test.queryError > *fs.PathError > *errors.errorString' " +
+		"error.query='SELECT 1' error.type='*fmt.wrapError' user=1"
+	if event.Message != expected {
+		t.Errorf("Unexpected message.\nExpected: %s\nGot:      %s", expected, event.Message)
+	}
+	if event.Parameters["error"] != err {
+		t.Errorf("Error parameter is not the original error")
+	}
+
+	joined := multiError{errors.New("first"), &queryError{query: "SELECT 2", err: errors.New("second")}}
+	sink.events = nil
+	source.With(map[string]any{"err": joined}).Warn("message")
+	expected = "message: err='first; query failed: second' " +
+		"err.0='first' " +
+		"err.1='query failed: second' err.1.cause='second' err.1.chain='*logtic_test.queryError > *errors.errorString' " +
+		"err.1.query='SELECT 2' err.1.type='*logtic_test.queryError' " +
+		"err.type='logtic_test.multiError'"
+	if len(sink.events) != 1 || sink.events[0].Message != expected {
+		t.Errorf("Unexpected message.\nExpected: %s\nGot:      %+v", expected, sink.events)
+	}
+
+	var nilError *queryError
+	sink.events = nil
+	source.PInfo("event", map[string]any{"error": nilError, "plain": errors.New("plain")})
+	if len(sink.events) != 1 || sink.events[0].Message != "event: error='<nil>' plain='plain'" {
+		t.Errorf("Unexpected events: %+v", sink.events)
+	}
+
+	logtic.Log.Options.ExpandErrors = false
+	sink.events = nil
+	source.ErrorE(err, "event", nil)
+	if len(sink.events) != 1 || len(sink.events[0].Parameters) != 1 {
+		t.Errorf("Errors expanded when disabled: %+v", sink.events)
+	}
+}
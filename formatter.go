@@ -0,0 +1,118 @@
+package logtic
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Formatter describes an interface for converting an event into a single line of output. Formatters should not include
+// a trailing newline.
+type Formatter interface {
+	Format(event Event) []byte
+}
+
+// TextFormatter formats events the same way as the log file, with the date-time in RFC-3339 format followed by the
+// level, source name, caller (if captured), and message. If the event has a trace context, the trace_id, span_id, and
+// trace_flags follow the message. If a stack was captured it follows on the next lines, indented by a tab. For example:
+//
+//	2021-03-15T21:43:34-07:00 [INFO][Example] This is a info message
+//	2021-03-15T21:43:34-07:00 [INFO][Example][server/handler.go:42] This is a info message with the caller
+type TextFormatter struct{}
+
+// Format returns the event as a line of text
+func (*TextFormatter) Format(event Event) []byte {
+	prefix := event.Time.Format(time.RFC3339) + " [" + event.Level.String() + "][" + event.Source + "]"
+	if event.Caller != nil {
+		prefix += "[" + event.Caller.String() + "]"
+	}
+	line := prefix + " " + event.Message
+	if event.Trace != nil {
+		line += " trace_id=" + event.Trace.TraceID + " span_id=" + event.Trace.SpanID + " trace_flags=" + event.Trace.flags()
+	}
+	if event.Stack != "" {
+		line += indentStack(event.Stack)
+	}
+	return []byte(line)
+}
+
+// JSONFormatter formats events as a single JSON object per line (JSON Lines). Parameters are included as typed JSON
+// values in the "parameters" object. For example:
+//
+//	{"time":"2021-03-15T21:43:34-07:00","level":"INFO","source":"Example","message":"Info event: param1='string'","event":"Info event","parameters":{"param1":"string"}}
+//
+// Byte slices are represented as hexadecimal strings, times in RFC-3339 format, and errors by their message. Values
+// that cannot be represented in JSON are formatted as strings.
+//
+// If the caller was captured it is included in the "caller" field, with the function name in the "function" field. A
+// captured stack is included in the "stack" field. The trace context is included in the "trace_id", "span_id", and
+// "trace_flags" fields.
+type JSONFormatter struct{}
+
+type tJSONEvent struct {
+	Time       string                     `json:"time"`
+	Level      string                     `json:"level"`
+	Source     string                     `json:"source"`
+	Message    string                     `json:"message"`
+	Event      string                     `json:"event,omitempty"`
+	Caller     string                     `json:"caller,omitempty"`
+	Function   string                     `json:"function,omitempty"`
+	Stack      string                     `json:"stack,omitempty"`
+	TraceID    string                     `json:"trace_id,omitempty"`
+	SpanID     string                     `json:"span_id,omitempty"`
+	TraceFlags string                     `json:"trace_flags,omitempty"`
+	Parameters map[string]json.RawMessage `json:"parameters,omitempty"`
+}
+
+// Format returns the event as a JSON object
+func (*JSONFormatter) Format(event Event) []byte {
+	e := tJSONEvent{
+		Time:    event.Time.Format(time.RFC3339),
+		Level:   event.Level.String(),
+		Source:  event.Source,
+		Message: event.Message,
+		Event:   event.Event,
+		Stack:   event.Stack,
+	}
+	if event.Trace != nil {
+		e.TraceID = event.Trace.TraceID
+		e.SpanID = event.Trace.SpanID
+		e.TraceFlags = event.Trace.flags()
+	}
+	if event.Caller != nil {
+		e.Caller = event.Caller.location()
+		e.Function = event.Caller.Function
+	}
+	if len(event.Parameters) > 0 {
+		e.Parameters = make(map[string]json.RawMessage, len(event.Parameters))
+		for k, v := range event.Parameters {
+			e.Parameters[k] = jsonParameter(v)
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		// Should never happen as every parameter has already been marshaled
+		return []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+	return data
+}
+
+func jsonParameter(v any) json.RawMessage {
+	switch t := v.(type) {
+	case []byte:
+		v = hex.EncodeToString(t)
+	case time.Time:
+		v = t.Format(time.RFC3339)
+	case error:
+		// Formatted with fmt as it handles nil values, such as a nil pointer to an error type
+		v = fmt.Sprintf("%v", t)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	return data
+}
@@ -0,0 +1,113 @@
+package logtic_test
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Formatter = &logtic.JSONFormatter{}
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	source := logtic.Log.Connect("test")
+	source.PInfo("Event", map[string]any{
+		"string": "it's a 'quoted' value",
+		"int":    123,
+		"float":  3.14,
+		"bool":   true,
+		"bytes":  []byte("Hello"),
+		"time":   time.Unix(0, 0).UTC(),
+		"nil":    nil,
+		"nan":    math.NaN(),
+		"error":  (*queryError)(nil),
+	})
+	logtic.Log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+
+	event := struct {
+		Time       string         `json:"time"`
+		Level      string         `json:"level"`
+		Source     string         `json:"source"`
+		Message    string         `json:"message"`
+		Event      string         `json:"event"`
+		Parameters map[string]any `json:"parameters"`
+	}{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("Error decoding log line '%s': %s", data, err.Error())
+	}
+
+	if _, err := time.Parse(time.RFC3339, event.Time); err != nil {
+		t.Errorf("Invalid event time '%s': %s", event.Time, err.Error())
+	}
+	if event.Level != "INFO" {
+		t.Errorf("Unexpected level. Expected 'INFO' got '%s'", event.Level)
+	}
+	if event.Source != "test" {
+		t.Errorf("Unexpected source. Expected 'test' got '%s'", event.Source)
+	}
+	if event.Event != "Event" {
+		t.Errorf("Unexpected event. Expected 'Event' got '%s'", event.Event)
+	}
+
+	check := func(key string, expected any) {
+		if event.Parameters[key] != expected {
+			t.Errorf("Unexpected value for parameter '%s'. Expected %#v got %#v", key, expected, event.Parameters[key])
+		}
+	}
+	check("string", "it's a 'quoted' value")
+	check("int", float64(123))
+	check("float", 3.14)
+	check("bool", true)
+	check("bytes", "48656c6c6f")
+	check("time", "1970-01-01T00:00:00Z")
+	check("nil", nil)
+	check("nan", "NaN")
+	check("error", "<nil>")
+
+	if t.Failed() {
+		t.Logf("Log file data:\n%s", data)
+	}
+}
+
+func TestFormatterCaller(t *testing.T) {
+	event := logtic.Event{
+		Time:    time.Unix(0, 0).UTC(),
+		Level:   logtic.LevelWarn,
+		Source:  "test",
+		Message: "message",
+		Caller: &logtic.Caller{
+			File:     "/src/app/server/handler.go",
+			Line:     42,
+			Function: "example.com/app/server.handle",
+		},
+	}
+
+	text := string((&logtic.TextFormatter{}).Format(event))
+	if text != "1970-01-01T00:00:00Z [WARN][test][server/handler.go:42 server.handle] message" {
+		t.Errorf("Unexpected text output '%s'", text)
+	}
+
+	data := string((&logtic.JSONFormatter{}).Format(event))
+	if data != `{"time":"1970-01-01T00:00:00Z","level":"WARN","source":"test","message":"message","caller":"server/handler.go:42","function":"example.com/app/server.handle"}` {
+		t.Errorf("Unexpected JSON output '%s'", data)
+	}
+}
@@ -21,11 +21,11 @@ type tGoLogger struct {
 func (t *tGoLogger) Write(p []byte) (n int, err error) {
 	var message string
 	if p[len(p)-1] == '\n' {
-		message = string(p[len(p)-1])
+		message = string(p[:len(p)-1])
 	} else {
 		message = string(p)
 	}
-	t.source.Write(t.level, message)
+	t.source.Write(t.level, "%s", message)
 
 	return len(p), nil
 }
@@ -0,0 +1,200 @@
+package logtic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// JournaldSink is a sink that writes events to the systemd journal using the native journal protocol. Journald is only
+// supported on Linux, on other platforms writing events will return an error.
+//
+// Each entry includes the MESSAGE, PRIORITY (based on the level of the event), SYSLOG_IDENTIFIER, and LOGTIC_SOURCE
+// fields, the CODE_FILE, CODE_LINE, and CODE_FUNC fields if the caller was captured, the LOGTIC_STACK field if the
+// stack was captured, and the TRACE_ID, SPAN_ID, and TRACE_FLAGS fields if the event has a trace context. Parameters
+// are included as fields with an uppercase name, any characters not permitted in journal field names are replaced with
+// an underscore. For example, the parameter "request-id" becomes the field "REQUEST_ID".
+//
+// Entries that are too large for a single datagram are passed to journald using a sealed memory file.
+type JournaldSink struct {
+	// SocketPath is the path to the journald socket. Defaults to /run/systemd/journal/socket.
+	SocketPath string
+	// Identifier is the SYSLOG_IDENTIFIER for all entries. Defaults to the name of the executable.
+	Identifier string
+
+	conn *tJournaldConn
+	lock sync.Mutex
+}
+
+const journaldSocketPath = "/run/systemd/journal/socket"
+
+// isJournalStream returns true if w is connected to the journal, by comparing the device and inode of the file with
+// the JOURNAL_STREAM environment variable set by systemd
+func isJournalStream(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	var device, inode uint64
+	if _, err := fmt.Sscanf(os.Getenv("JOURNAL_STREAM"), "%d:%d", &device, &inode); err != nil {
+		return false
+	}
+	return fileDeviceInode(f, device, inode)
+}
+
+// journaldReservedFields are the fields written by logtic which parameters may not replace
+var journaldReservedFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"LOGTIC_SOURCE":     true,
+	"LOGTIC_EVENT":      true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+	"CODE_FUNC":         true,
+	"LOGTIC_STACK":      true,
+	"TRACE_ID":          true,
+	"SPAN_ID":           true,
+	"TRACE_FLAGS":       true,
+}
+
+// NewJournaldSink will create a new journald sink using the default socket path
+func NewJournaldSink() *JournaldSink {
+	return &JournaldSink{
+		SocketPath: journaldSocketPath,
+		Identifier: filepath.Base(os.Args[0]),
+	}
+}
+
+// WriteEvent sends the event to journald, connecting or reconnecting if needed
+func (s *JournaldSink) WriteEvent(event Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	socketPath := s.SocketPath
+	if socketPath == "" {
+		socketPath = journaldSocketPath
+	}
+	entry := s.entry(event)
+
+	// Try once more with a new connection if the existing connection was lost, such as when journald restarts
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			conn, err := dialJournald(socketPath)
+			if err != nil {
+				return err
+			}
+			s.conn = conn
+		}
+		if err = s.conn.send(entry); err == nil {
+			return nil
+		}
+		s.conn.close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection to journald
+func (s *JournaldSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.close()
+	s.conn = nil
+	return err
+}
+
+func (s *JournaldSink) entry(event Event) []byte {
+	identifier := s.Identifier
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	b := &bytes.Buffer{}
+	journaldField(b, "MESSAGE", event.Message)
+	journaldField(b, "PRIORITY", strconv.Itoa(syslogSeverity(event.Level)))
+	journaldField(b, "SYSLOG_IDENTIFIER", identifier)
+	journaldField(b, "LOGTIC_SOURCE", event.Source)
+	if event.Event != "" {
+		journaldField(b, "LOGTIC_EVENT", event.Event)
+	}
+	if event.Caller != nil {
+		journaldField(b, "CODE_FILE", event.Caller.File)
+		journaldField(b, "CODE_LINE", strconv.Itoa(event.Caller.Line))
+		if event.Caller.Function != "" {
+			journaldField(b, "CODE_FUNC", event.Caller.Function)
+		}
+	}
+	if event.Stack != "" {
+		journaldField(b, "LOGTIC_STACK", event.Stack)
+	}
+	if event.Trace != nil {
+		journaldField(b, "TRACE_ID", event.Trace.TraceID)
+		journaldField(b, "SPAN_ID", event.Trace.SpanID)
+		journaldField(b, "TRACE_FLAGS", event.Trace.flags())
+	}
+
+	keys := make([]string, 0, len(event.Parameters))
+	for k := range event.Parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := journaldFieldName(k)
+		if journaldReservedFields[name] {
+			name = "PARAM_" + name
+		}
+		value, _ := parameterString(event.Parameters[k])
+		journaldField(b, name, value)
+	}
+
+	return b.Bytes()
+}
+
+// journaldField writes a single field to b using the native journal protocol. Values containing a newline are written
+// using the binary length-prefixed form.
+func journaldField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journaldFieldName returns a valid journal field name for the given parameter key. Field names may only contain
+// uppercase letters, numbers, and underscores, must not start with an underscore or number, and may be at most 64
+// characters long.
+func journaldFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] == '_' || (name[0] >= '0' && name[0] <= '9') {
+		name = append([]byte("P"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
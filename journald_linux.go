@@ -0,0 +1,137 @@
+//go:build linux
+
+package logtic
+
+import (
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+type tJournaldConn struct {
+	conn *net.UnixConn
+}
+
+func dialJournald(socketPath string) (*tJournaldConn, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &tJournaldConn{conn: conn}, nil
+}
+
+func (c *tJournaldConn) send(entry []byte) error {
+	_, err := c.conn.Write(entry)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	// The entry is too large for a datagram, so write it to a file and pass the descriptor to journald instead
+	f, err := journaldEntryFile(entry)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rawConn, err := c.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	var sendErr error
+	err = rawConn.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
+
+func (c *tJournaldConn) close() error {
+	return c.conn.Close()
+}
+
+// memfdCreateSyscall is the memfd_create system call number for each architecture
+var memfdCreateSyscall = map[string]uintptr{
+	"386":      356,
+	"amd64":    319,
+	"arm":      385,
+	"arm64":    279,
+	"loong64":  279,
+	"mips64":   5314,
+	"mips64le": 5314,
+	"ppc64":    360,
+	"ppc64le":  360,
+	"riscv64":  279,
+	"s390x":    350,
+}
+
+const (
+	memfdAllowSealing = 0x2
+	fcntlAddSeals     = 1033
+	sealAll           = 0x1 | 0x2 | 0x4 | 0x8 // F_SEAL_SEAL | F_SEAL_SHRINK | F_SEAL_GROW | F_SEAL_WRITE
+)
+
+// journaldEntryFile returns a file containing entry that can be passed to journald. A sealed memfd is used when
+// possible, otherwise an unlinked file in /dev/shm.
+func journaldEntryFile(entry []byte) (*os.File, error) {
+	if f, err := journaldMemfd(entry); err == nil {
+		return f, nil
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "logtic-journal-")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	if _, err := f.Write(entry); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func journaldMemfd(entry []byte) (*os.File, error) {
+	trap, ok := memfdCreateSyscall[runtime.GOARCH]
+	if !ok {
+		return nil, syscall.ENOSYS
+	}
+
+	name, err := syscall.BytePtrFromString("logtic-journal")
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(trap, uintptr(unsafe.Pointer(name)), memfdAllowSealing, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+
+	f := os.NewFile(fd, "logtic-journal")
+	if _, err := f.Write(entry); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, fcntlAddSeals, sealAll); errno != 0 {
+		f.Close()
+		return nil, errno
+	}
+	return f, nil
+}
+
+// fileDeviceInode returns true if f is the file with the given device and inode
+func fileDeviceInode(f *os.File, device, inode uint64) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && uint64(stat.Dev) == device && uint64(stat.Ino) == inode
+}
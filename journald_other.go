@@ -0,0 +1,26 @@
+//go:build !linux
+
+package logtic
+
+import (
+	"fmt"
+	"os"
+)
+
+type tJournaldConn struct{}
+
+func dialJournald(socketPath string) (*tJournaldConn, error) {
+	return nil, fmt.Errorf("journald is only supported on linux")
+}
+
+func (c *tJournaldConn) send(entry []byte) error {
+	return fmt.Errorf("journald is only supported on linux")
+}
+
+func (c *tJournaldConn) close() error {
+	return nil
+}
+
+func fileDeviceInode(f *os.File, device, inode uint64) bool {
+	return false
+}
@@ -0,0 +1,178 @@
+//go:build linux
+
+package logtic_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestJournaldSink(t *testing.T) {
+	Setup()
+
+	socketPath := path.Join(t.TempDir(), "socket")
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Error listening: %s", err.Error())
+	}
+	defer listener.Close()
+	listener.SetReadBuffer(4 * 1024 * 1024)
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := logtic.NewJournaldSink()
+	sink.SocketPath = socketPath
+	sink.Identifier = "myapp"
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.PWarn("event", map[string]any{"request-id": 123, "multi": "line\nvalue", "message": "duplicate"})
+
+	buf := make([]byte, 1024)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("Error reading entry: %s", err.Error())
+	}
+
+	multi := &bytes.Buffer{}
+	multi.WriteString("MULTI\n")
+	binary.Write(multi, binary.LittleEndian, uint64(10))
+	multi.WriteString("line\nvalue\n")
+
+	expected := "MESSAGE=event: message='duplicate' multi='line\\nvalue' request-id=123\n" +
+		"PRIORITY=4\n" +
+		"SYSLOG_IDENTIFIER=myapp\n" +
+		"LOGTIC_SOURCE=test\n" +
+		"LOGTIC_EVENT=event\n" +
+		"PARAM_MESSAGE=duplicate\n" +
+		multi.String() +
+		"REQUEST_ID=123\n"
+	if string(buf[:n]) != expected {
+		t.Errorf("Unexpected journal entry.\nExpected:\n%q\nGot:\n%q", expected, buf[:n])
+	}
+
+	// Entries larger than the maximum datagram size are passed as a file descriptor
+	large := strings.Repeat("a", 1024*1024)
+	source.Info("%s", large)
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_, oobn, _, _, err := listener.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("Error reading entry: %s", err.Error())
+	}
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Large entry was not passed as a file descriptor")
+	}
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("Large entry was not passed as a file descriptor")
+	}
+	f := os.NewFile(uintptr(fds[0]), "entry")
+	defer f.Close()
+	f.Seek(0, 0)
+	data := make([]byte, len(large)+100)
+	n, _ = f.Read(data)
+	if !strings.HasPrefix(string(data[:n]), "MESSAGE="+large+"\nPRIORITY=6\n") {
+		t.Errorf("Unexpected large journal entry")
+	}
+
+	logtic.Log.Close()
+}
+
+func TestJournalPrefix(t *testing.T) {
+	journal, err := os.Create(path.Join(t.TempDir(), "journal"))
+	if err != nil {
+		t.Fatalf("Error creating file: %s", err.Error())
+	}
+	defer journal.Close()
+	other, err := os.Create(path.Join(t.TempDir(), "other"))
+	if err != nil {
+		t.Fatalf("Error creating file: %s", err.Error())
+	}
+	defer other.Close()
+	stat := &syscall.Stat_t{}
+	if err := syscall.Fstat(int(journal.Fd()), stat); err != nil {
+		t.Fatalf("Error getting file info: %s", err.Error())
+	}
+	t.Setenv("JOURNAL_STREAM", fmt.Sprintf("%d:%d", stat.Dev, stat.Ino))
+
+	// Only events written to the file connected to the journal are prefixed
+	Setup()
+	logtic.Log.Stdout = journal
+	logtic.Log.Stderr = other
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Options.JournalPrefix = true
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	source := logtic.Log.Connect("test")
+	source.Info("with prefix")
+	source.Error("without prefix")
+	logtic.Log.Close()
+
+	check := func(f *os.File, expected string) {
+		data, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatalf("Error reading file: %s", err.Error())
+		}
+		if string(data) != expected {
+			t.Errorf("Unexpected console output.\nExpected:\n%q\nGot:\n%q", expected, data)
+		}
+	}
+	check(journal, "<6>[INFO][test] with prefix\n")
+	check(other, "\x1b[31m[ERROR][test]\x1b[0m without prefix\n")
+}
+
+func TestJournaldSinkReconnect(t *testing.T) {
+	socketPath := path.Join(t.TempDir(), "socket")
+	listen := func() *net.UnixConn {
+		listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+		if err != nil {
+			t.Fatalf("Error listening: %s", err.Error())
+		}
+		return listener
+	}
+
+	sink := logtic.NewJournaldSink()
+	sink.SocketPath = socketPath
+	defer sink.Close()
+	event := logtic.Event{Level: logtic.LevelInfo, Source: "test", Message: "hello"}
+
+	listener := listen()
+	if err := sink.WriteEvent(event); err != nil {
+		t.Fatalf("Error writing event: %s", err.Error())
+	}
+
+	// Simulate journald restarting
+	listener.Close()
+	os.Remove(socketPath)
+	listener = listen()
+	defer listener.Close()
+
+	if err := sink.WriteEvent(event); err != nil {
+		t.Fatalf("Error writing event after restart: %s", err.Error())
+	}
+	buf := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("Error reading entry: %s", err.Error())
+	}
+	if !strings.HasPrefix(string(buf[:n]), "MESSAGE=hello\n") {
+		t.Errorf("Unexpected journal entry: %q", buf[:n])
+	}
+}
@@ -1,14 +1,156 @@
 package logtic
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LogLevel describes the severity of an event. The numeric values of LevelDebug, LevelInfo, LevelWarn, and LevelError
+// are unchanged from earlier versions, so the values of the levels are not in order of severity. LogLevel implements
+// encoding.TextMarshaler, encoding.TextUnmarshaler, and flag.Value; so it may be used directly in configuration files
+// or command line flags. For example:
+//
+//	flag.Var(&logtic.Log.Level, "log-level", "The minimum log level")
 type LogLevel int
 
 const (
+	// LevelTrace very detailed messages for tracing application behaviour, such as protocol dumps
+	LevelTrace = LogLevel(4)
 	// LevelDebug debug messages for troubleshooting application behaviour
 	LevelDebug = LogLevel(3)
 	// LevelInfo informational messages for normal operation of the application
 	LevelInfo = LogLevel(2)
+	// LevelNotice normal but significant messages
+	LevelNotice = LogLevel(5)
 	// LevelWarn warning messages for potential issues
 	LevelWarn = LogLevel(1)
 	// LevelError error messages for problems
 	LevelError = LogLevel(0)
+	// LevelCritical critical messages for problems that need immediate attention
+	LevelCritical = LogLevel(-1)
+
+	// levelFatal is used for events from Fatal and Panic, which are always captured regardless of the level
+	levelFatal = LogLevel(-2)
 )
+
+var levelNames = map[LogLevel]string{
+	LevelTrace:    "TRACE",
+	LevelDebug:    "DEBUG",
+	LevelInfo:     "INFO",
+	LevelNotice:   "NOTICE",
+	LevelWarn:     "WARN",
+	LevelError:    "ERROR",
+	LevelCritical: "CRITICAL",
+	levelFatal:    "FATAL",
+}
+
+// levelsByVerbosity are the levels from the least verbose to the most verbose
+var levelsByVerbosity = []LogLevel{
+	levelFatal,
+	LevelCritical,
+	LevelError,
+	LevelWarn,
+	LevelNotice,
+	LevelInfo,
+	LevelDebug,
+	LevelTrace,
+}
+
+// levelAliases are additional names accepted by ParseLevel
+var levelAliases = map[string]LogLevel{
+	"WARNING": LevelWarn,
+}
+
+// ParseLevel returns the level matching the given name. Names are not case sensitive. For example, "debug", "Debug",
+// and "DEBUG" all return LevelDebug. "warning" is also accepted for LevelWarn.
+func ParseLevel(name string) (LogLevel, error) {
+	upperName := strings.ToUpper(strings.TrimSpace(name))
+	for level, levelName := range levelNames {
+		if levelName == upperName && level != levelFatal {
+			return level, nil
+		}
+	}
+	if level, ok := levelAliases[upperName]; ok {
+		return level, nil
+	}
+	return 0, fmt.Errorf("unknown log level '%s'", name)
+}
+
+// String returns the name of the level, as it appears in log lines. For example, "DEBUG".
+func (level LogLevel) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(level))
+}
+
+// Set parses the given name and sets the level to it. Set implements the flag.Value interface.
+func (level *LogLevel) Set(name string) error {
+	l, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	*level = l
+	return nil
+}
+
+// MarshalText returns the name of the level
+func (level LogLevel) MarshalText() ([]byte, error) {
+	if _, ok := levelNames[level]; !ok || level == levelFatal {
+		return nil, fmt.Errorf("unknown log level %d", int(level))
+	}
+	return []byte(level.String()), nil
+}
+
+// UnmarshalText parses the name of a level
+func (level *LogLevel) UnmarshalText(text []byte) error {
+	return level.Set(string(text))
+}
+
+// UnmarshalJSON parses either the name of a level as a string or the numeric value of a level. Unknown levels are
+// rejected.
+func (level *LogLevel) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		if _, ok := levelNames[LogLevel(number)]; !ok || LogLevel(number) == levelFatal {
+			return fmt.Errorf("unknown log level %d", number)
+		}
+		*level = LogLevel(number)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("log level must be a string or number")
+	}
+	return level.Set(name)
+}
+
+// verbosity returns the position of the level in levelsByVerbosity. Unknown levels above LevelNotice are treated as
+// more verbose than LevelTrace, and unknown levels below levelFatal as less verbose than it.
+func (level LogLevel) verbosity() int {
+	for i, l := range levelsByVerbosity {
+		if l == level {
+			return i
+		}
+	}
+	if level > LevelNotice {
+		return len(levelsByVerbosity) + int(level-LevelNotice)
+	}
+	return int(level - levelFatal)
+}
+
+// allows returns true if events of the given level are captured when level is the minimum level
+func (level LogLevel) allows(eventLevel LogLevel) bool {
+	return eventLevel.verbosity() <= level.verbosity()
+}
+
+// moreVerbose returns the next more verbose level, or false if the level is LevelTrace
+func (level LogLevel) moreVerbose() (LogLevel, bool) {
+	i := level.verbosity() + 1
+	if i < 1 || i >= len(levelsByVerbosity) {
+		return level, false
+	}
+	return levelsByVerbosity[i], true
+}
@@ -0,0 +1,224 @@
+package logtic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// LevelHandler returns a http.Handler for viewing and changing the log levels of this instance at runtime. The handler
+// does not perform any authentication, so it should only be exposed on a trusted interface or wrapped by a handler that
+// does.
+//
+// A GET request returns the current levels and the names of all connected sources:
+//
+//	{
+//	    "level": "INFO",
+//	    "source_levels": {
+//	        "db": { "level": "DEBUG", "expires": "2026-01-01T12:00:00Z" }
+//	    },
+//	    "sources": [ "db", "http" ]
+//	}
+//
+// A PUT request changes the level of the instance, or of a source name or pattern if "source" is set. If "duration"
+// is set the change is reverted automatically after that amount of time:
+//
+//	{ "source": "db", "level": "debug", "duration": "10m" }
+//
+// A DELETE request with a "source" query parameter clears the level for that source name or pattern.
+//
+// All requests respond with the current levels.
+func (l *Logger) LevelHandler() http.Handler {
+	return &tLevelHandler{instance: l}
+}
+
+type tLevelHandler struct {
+	instance *Logger
+}
+
+type tLevelTimer struct {
+	timer       *time.Timer
+	expires     time.Time
+	previous    LogLevel
+	hadPrevious bool
+}
+
+type tLevelState struct {
+	Level        LogLevel                     `json:"level"`
+	Expires      *time.Time                   `json:"expires,omitempty"`
+	SourceLevels map[string]tSourceLevelState `json:"source_levels"`
+	Sources      []string                     `json:"sources"`
+}
+
+type tSourceLevelState struct {
+	Level   LogLevel   `json:"level"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type tLevelRequest struct {
+	Source   *string   `json:"source"`
+	Level    *LogLevel `json:"level"`
+	Duration string    `json:"duration"`
+}
+
+func (h *tLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		break
+	case http.MethodPut:
+		request := tLevelRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if request.Level == nil {
+			http.Error(w, "invalid request: level is required", http.StatusBadRequest)
+			return
+		}
+		if _, known := levelNames[*request.Level]; !known || *request.Level == levelFatal {
+			http.Error(w, fmt.Sprintf("invalid request: unknown log level %d", *request.Level), http.StatusBadRequest)
+			return
+		}
+		var duration time.Duration
+		if request.Duration != "" {
+			d, err := time.ParseDuration(request.Duration)
+			if err != nil || d <= 0 {
+				http.Error(w, fmt.Sprintf("invalid duration '%s'", request.Duration), http.StatusBadRequest)
+				return
+			}
+			duration = d
+		}
+		if request.Source != nil {
+			h.instance.setSourceLevelFor(*request.Source, *request.Level, duration)
+		} else {
+			h.instance.setLevelFor(*request.Level, duration)
+		}
+	case http.MethodDelete:
+		source := r.URL.Query()["source"]
+		if len(source) == 0 {
+			http.Error(w, "invalid request: source is required", http.StatusBadRequest)
+			return
+		}
+		h.instance.ClearSourceLevel(source[0])
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.instance.levelState())
+}
+
+func (l *Logger) levelState() tLevelState {
+	l.levelLock.RLock()
+	defer l.levelLock.RUnlock()
+
+	state := tLevelState{
+		Level:        l.Level,
+		SourceLevels: map[string]tSourceLevelState{},
+		Sources:      make([]string, 0, len(l.sources)),
+	}
+	if l.levelTimer != nil {
+		expires := l.levelTimer.expires
+		state.Expires = &expires
+	}
+	for name, level := range l.sourceLevels {
+		sourceState := tSourceLevelState{Level: level}
+		if timer := l.levelTimers[name]; timer != nil {
+			expires := timer.expires
+			sourceState.Expires = &expires
+		}
+		state.SourceLevels[name] = sourceState
+	}
+	for name := range l.sources {
+		state.Sources = append(state.Sources, name)
+	}
+	sort.Strings(state.Sources)
+	return state
+}
+
+// setLevelFor sets the level of this instance, reverting to the previous level after duration if it is not zero
+func (l *Logger) setLevelFor(level LogLevel, duration time.Duration) {
+	l.levelLock.Lock()
+	defer l.levelLock.Unlock()
+
+	// Replacing a pending temporary level keeps the original level to revert to
+	previous := l.Level
+	if l.levelTimer != nil {
+		l.levelTimer.timer.Stop()
+		previous = l.levelTimer.previous
+		l.levelTimer = nil
+	}
+	l.Level = level
+	if duration <= 0 {
+		return
+	}
+
+	levelTimer := &tLevelTimer{expires: time.Now().Add(duration), previous: previous}
+	levelTimer.timer = time.AfterFunc(duration, func() {
+		l.levelLock.Lock()
+		defer l.levelLock.Unlock()
+		if l.levelTimer != levelTimer {
+			return
+		}
+		l.Level = levelTimer.previous
+		l.levelTimer = nil
+	})
+	l.levelTimer = levelTimer
+}
+
+// setSourceLevelFor sets the level of the given source name or pattern, reverting to the previous level after duration
+// if it is not zero
+func (l *Logger) setSourceLevelFor(name string, level LogLevel, duration time.Duration) {
+	l.levelLock.Lock()
+	defer l.levelLock.Unlock()
+
+	previous, hadPrevious := l.sourceLevels[name]
+	if levelTimer := l.levelTimers[name]; levelTimer != nil {
+		previous, hadPrevious = levelTimer.previous, levelTimer.hadPrevious
+	}
+	l.stopSourceLevelTimer(name)
+	l.setSourceLevel(name, level)
+	if duration <= 0 {
+		return
+	}
+
+	levelTimer := &tLevelTimer{expires: time.Now().Add(duration), previous: previous, hadPrevious: hadPrevious}
+	levelTimer.timer = time.AfterFunc(duration, func() {
+		l.levelLock.Lock()
+		defer l.levelLock.Unlock()
+		if l.levelTimers[name] != levelTimer {
+			return
+		}
+		delete(l.levelTimers, name)
+		if levelTimer.hadPrevious {
+			l.setSourceLevel(name, levelTimer.previous)
+		} else {
+			l.clearSourceLevel(name)
+		}
+	})
+	if l.levelTimers == nil {
+		l.levelTimers = map[string]*tLevelTimer{}
+	}
+	l.levelTimers[name] = levelTimer
+}
+
+func (l *Logger) stopSourceLevelTimer(name string) {
+	if levelTimer := l.levelTimers[name]; levelTimer != nil {
+		levelTimer.timer.Stop()
+		delete(l.levelTimers, name)
+	}
+}
+
+func (l *Logger) stopLevelTimers() {
+	if l.levelTimer != nil {
+		l.levelTimer.timer.Stop()
+		l.levelTimer = nil
+	}
+	for name := range l.levelTimers {
+		l.stopSourceLevelTimer(name)
+	}
+}
@@ -0,0 +1,133 @@
+package logtic_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type levelState struct {
+	Level        string `json:"level"`
+	Expires      string `json:"expires"`
+	SourceLevels map[string]struct {
+		Level   string `json:"level"`
+		Expires string `json:"expires"`
+	} `json:"source_levels"`
+	Sources []string `json:"sources"`
+}
+
+func levelRequest(t *testing.T, handler http.Handler, method, target, body string) (int, levelState) {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	state := levelState{}
+	if response.Code == http.StatusOK {
+		if err := json.Unmarshal(response.Body.Bytes(), &state); err != nil {
+			t.Fatalf("Error decoding response '%s': %s", response.Body.String(), err.Error())
+		}
+	}
+	return response.Code, state
+}
+
+func TestLevelHandler(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	db := logtic.Log.Connect("db")
+	logtic.Log.Connect("http").Sub("api")
+	handler := logtic.Log.LevelHandler()
+
+	code, state := levelRequest(t, handler, http.MethodGet, "/", "")
+	if code != http.StatusOK {
+		t.Fatalf("Unexpected status code %d", code)
+	}
+	if state.Level != "INFO" {
+		t.Errorf("Unexpected level. Expected 'INFO' got '%s'", state.Level)
+	}
+	if strings.Join(state.Sources, ",") != "db,http,http/api" {
+		t.Errorf("Unexpected sources: %v", state.Sources)
+	}
+
+	code, state = levelRequest(t, handler, http.MethodPut, "/", `{"level":"warn"}`)
+	if code != http.StatusOK {
+		t.Fatalf("Unexpected status code %d", code)
+	}
+	if state.Level != "WARN" || logtic.Log.Level != logtic.LevelWarn {
+		t.Errorf("Instance level not changed")
+	}
+
+	code, state = levelRequest(t, handler, http.MethodPut, "/", `{"source":"db","level":"debug"}`)
+	if code != http.StatusOK {
+		t.Fatalf("Unexpected status code %d", code)
+	}
+	if state.SourceLevels["db"].Level != "DEBUG" {
+		t.Errorf("Source level not changed: %v", state.SourceLevels)
+	}
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	db.Debug("test")
+	if len(sink.events) != 1 {
+		t.Errorf("Debug event not written for source with debug level")
+	}
+
+	code, state = levelRequest(t, handler, http.MethodDelete, "/?source=db", "")
+	if code != http.StatusOK {
+		t.Fatalf("Unexpected status code %d", code)
+	}
+	if _, ok := state.SourceLevels["db"]; ok {
+		t.Errorf("Source level not cleared: %v", state.SourceLevels)
+	}
+
+	for _, body := range []string{`{"level":"fatal"}`, `{"level":-2}`, `{}`, `{"level":"info","duration":"soon"}`, `nope`} {
+		if code, _ := levelRequest(t, handler, http.MethodPut, "/", body); code != http.StatusBadRequest {
+			t.Errorf("Unexpected status code %d for request '%s'", code, body)
+		}
+	}
+	if code, _ := levelRequest(t, handler, http.MethodPost, "/", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("Unexpected status code %d for POST request", code)
+	}
+}
+
+func TestLevelHandlerDuration(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	logtic.Log.SetSourceLevel("db", logtic.LevelWarn)
+	handler := logtic.Log.LevelHandler()
+
+	_, state := levelRequest(t, handler, http.MethodPut, "/", `{"level":"debug","duration":"50ms"}`)
+	if state.Level != "DEBUG" || state.Expires == "" {
+		t.Errorf("Unexpected temporary level '%s' expires '%s'", state.Level, state.Expires)
+	}
+	levelRequest(t, handler, http.MethodPut, "/", `{"source":"db","level":"trace","duration":"50ms"}`)
+	// Replacing a temporary level must still revert to the original level
+	levelRequest(t, handler, http.MethodPut, "/", `{"source":"db","level":"debug","duration":"50ms"}`)
+	levelRequest(t, handler, http.MethodPut, "/", `{"source":"cache","level":"debug","duration":"50ms"}`)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, state = levelRequest(t, handler, http.MethodGet, "/", "")
+		_, cacheSet := state.SourceLevels["cache"]
+		if state.Level == "INFO" && state.SourceLevels["db"].Level == "WARN" && !cacheSet {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Temporary levels not reverted: %+v", state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if state.Expires != "" || state.SourceLevels["db"].Expires != "" {
+		t.Errorf("Reverted levels still have an expiry: %+v", state)
+	}
+}
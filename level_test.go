@@ -0,0 +1,135 @@
+package logtic_test
+
+import (
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestParseLevel(t *testing.T) {
+	test := func(in string, expected logtic.LogLevel) {
+		level, err := logtic.ParseLevel(in)
+		if err != nil {
+			t.Errorf("Unexpected error parsing level '%s': %s", in, err.Error())
+			return
+		}
+		if level != expected {
+			t.Errorf("Unexpected level for '%s'. Expected %s got %s", in, expected, level)
+		}
+	}
+
+	test("trace", logtic.LevelTrace)
+	test("DEBUG", logtic.LevelDebug)
+	test("Info", logtic.LevelInfo)
+	test("notice", logtic.LevelNotice)
+	test("warn", logtic.LevelWarn)
+	test(" warning ", logtic.LevelWarn)
+	test("error", logtic.LevelError)
+	test("critical", logtic.LevelCritical)
+
+	for _, in := range []string{"", "fatal", "verbose", "3"} {
+		if _, err := logtic.ParseLevel(in); err == nil {
+			t.Errorf("No error seen when one expected for level '%s'", in)
+		}
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	if s := logtic.LevelWarn.String(); s != "WARN" {
+		t.Errorf("Unexpected string for level. Expected 'WARN' got '%s'", s)
+	}
+	if s := logtic.LogLevel(9001).String(); s != "LogLevel(9001)" {
+		t.Errorf("Unexpected string for unknown level. Expected 'LogLevel(9001)' got '%s'", s)
+	}
+}
+
+func TestLevelJSON(t *testing.T) {
+	type config struct {
+		Level logtic.LogLevel `json:"level"`
+	}
+
+	data, err := json.Marshal(config{Level: logtic.LevelDebug})
+	if err != nil {
+		t.Fatalf("Error encoding level: %s", err.Error())
+	}
+	if string(data) != `{"level":"DEBUG"}` {
+		t.Errorf("Unexpected JSON for level: %s", data)
+	}
+
+	c := config{}
+	if err := json.Unmarshal([]byte(`{"level":"notice"}`), &c); err != nil {
+		t.Fatalf("Error decoding level: %s", err.Error())
+	}
+	if c.Level != logtic.LevelNotice {
+		t.Errorf("Unexpected level decoded from string: %s", c.Level)
+	}
+
+	if err := json.Unmarshal([]byte(`{"level":1}`), &c); err != nil {
+		t.Fatalf("Error decoding level: %s", err.Error())
+	}
+	if c.Level != logtic.LevelWarn {
+		t.Errorf("Unexpected level decoded from number: %s", c.Level)
+	}
+
+	// Numeric values from earlier versions keep their meaning
+	if err := json.Unmarshal([]byte(`{"level":2}`), &c); err != nil {
+		t.Fatalf("Error decoding level: %s", err.Error())
+	}
+	if c.Level != logtic.LevelInfo {
+		t.Errorf("Unexpected level decoded from number: %s", c.Level)
+	}
+
+	for _, invalid := range []string{`{"level":"loud"}`, `{"level":42}`, `{"level":-2}`} {
+		if err := json.Unmarshal([]byte(invalid), &c); err == nil {
+			t.Errorf("No error seen when one expected for unknown level %s", invalid)
+		}
+	}
+}
+
+func TestLevelFlag(t *testing.T) {
+	level := logtic.LevelError
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&level, "log-level", "")
+
+	if err := flags.Parse([]string{"-log-level", "debug"}); err != nil {
+		t.Fatalf("Error parsing flags: %s", err.Error())
+	}
+	if level != logtic.LevelDebug {
+		t.Errorf("Unexpected level from flag: %s", level)
+	}
+}
+
+func TestLevelOrder(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	source := logtic.Log.Connect("test")
+	source.Trace("trace")
+	source.Debug("debug")
+	source.Info("info")
+	source.Notice("notice")
+	source.Warn("warn")
+	source.Critical("critical")
+
+	logtic.Log.Level = logtic.LevelWarn
+	source.Info("info")
+	source.Notice("notice")
+	source.Error("error")
+
+	messages := []string{}
+	for _, event := range sink.events {
+		messages = append(messages, event.Message)
+	}
+	if strings.Join(messages, ",") != "info,notice,warn,critical,error" {
+		t.Errorf("Unexpected events: %v", messages)
+	}
+}
@@ -1,14 +1,20 @@
 package logtic
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Logger describes a logging instance
 type Logger struct {
+	// dropped is first so that it is 64-bit aligned for atomic operations on 32-bit platforms
+	dropped uint64
+
 	// The path to the log file.
 	FilePath string
 	// The minimum level of events captured in the log file and printed to console. Inclusive.
@@ -23,10 +29,41 @@ type Logger struct {
 	Stderr io.Writer
 	// Color is the interface to apply color to messages. Defaults to using ANSI color codes.
 	Color IColor
+	// Async describes if events are written in the background by this logging instance. Changes only take effect when
+	// the instance is opened. By default, events are written synchronously.
+	Async AsyncOptions
+	// Rotation is the policy for automatically rotating the log file. By default, log files are never rotated
+	// automatically.
+	Rotation RotatePolicy
+	// Formatter is used to format events written to the log file. Defaults to a TextFormatter, use a JSONFormatter to
+	// write each event as a JSON object.
+	Formatter Formatter
+	// Redaction is the policy for redacting sensitive parameters and parts of messages. By default, nothing is redacted
+	// except for Secret values. Use DefaultRedactPolicy for a policy that covers common sensitive parameters.
+	Redaction RedactPolicy
+
+	opened      bool
+	file        *os.File
+	fileSize    int64
+	fileTime    time.Time
+	fileRegular bool
+	rotatedPath string
+	compressing sync.WaitGroup
+	queue       *tEventQueue
+	queueLock   sync.RWMutex
+	sinks       []tSinkEntry
+	lock        sync.Mutex
 
-	opened bool
-	file   *os.File
-	lock   sync.Mutex
+	// journalStdout and journalStderr are set when the instance is opened if the writer is connected to the journal
+	journalStdout bool
+	journalStderr bool
+
+	sourceLevels  map[string]LogLevel
+	levelPatterns []string
+	levelTimers   map[string]*tLevelTimer
+	levelTimer    *tLevelTimer
+	sources       map[string]struct{}
+	levelLock     sync.RWMutex
 }
 
 // LoggerOptions describe logger options
@@ -34,11 +71,37 @@ type LoggerOptions struct {
 	// Should logtic escape control characters automatically. For example, replaces actual newlines with a literal \n.
 	// Enabled by default.
 	EscapeCharacters bool
+	// Should logtic prefix events printed to the console with a sd-daemon priority prefix, such as <6>, when running as
+	// a systemd service with the console connected to the journal. This is detected when the instance is opened by
+	// comparing Stdout and Stderr with the JOURNAL_STREAM environment variable. Colors are not applied when the prefix
+	// is used. Disabled by default.
+	JournalPrefix bool
+	// Should logtic expand error parameters to include the type of the error, the chain of wrapped errors, the root
+	// cause, and any fields from errors implementing ErrorWithFields. Plain errors created by errors.New, which do not
+	// wrap another error, are not expanded. Enabled by default.
+	ExpandErrors bool
+	// Should logtic capture the file and line of the code that wrote each event. The caller is included in the log
+	// file, console, and sinks. Disabled by default.
+	Caller bool
+	// The least severe level of events where the caller is captured, if Caller is enabled. For example, LevelWarn only
+	// captures the caller for warning events and above. Defaults to LevelTrace for instances created with New or reset
+	// with Reset. The zero value is LevelError, so set this when assigning a new LoggerOptions value to Options.
+	CallerLevel LogLevel
+	// Should logtic include the name of the function with the caller, if Caller is enabled. Disabled by default.
+	CallerFunction bool
+	// Should logtic capture the stack of the goroutine that wrote an event. The stack is written on the lines following
+	// the event in the log file and console, and is included in sinks. Disabled by default.
+	Stack bool
+	// The least severe level of events where the stack is captured, if Stack is enabled. Defaults to LevelError.
+	StackLevel LogLevel
 }
 
 func defaultLoggerOption() LoggerOptions {
 	return LoggerOptions{
 		EscapeCharacters: true,
+		ExpandErrors:     true,
+		CallerLevel:      LevelTrace,
+		StackLevel:       LevelError,
 	}
 }
 
@@ -49,13 +112,14 @@ var Log = New()
 // logging instance from the default instance, which is automatically created for you.
 func New() *Logger {
 	return &Logger{
-		FilePath: os.DevNull,
-		Level:    LevelError,
-		FileMode: 0644,
-		Options:  defaultLoggerOption(),
-		Stdout:   os.Stdout,
-		Stderr:   os.Stderr,
-		Color:    &tDefaultColor{},
+		FilePath:  os.DevNull,
+		Level:     LevelError,
+		FileMode:  0644,
+		Options:   defaultLoggerOption(),
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+		Color:     &tDefaultColor{},
+		Formatter: &TextFormatter{},
 	}
 }
 
@@ -63,7 +127,21 @@ func New() *Logger {
 // not already exist, otherwise it will be appended to.
 func (l *Logger) Open() error {
 	l.opened = true
+	l.journalStdout = l.Options.JournalPrefix && isJournalStream(l.Stdout)
+	l.journalStderr = l.Options.JournalPrefix && isJournalStream(l.Stderr)
+
+	if l.Async.Enabled {
+		l.queueLock.Lock()
+		if l.queue == nil {
+			l.queue = l.startQueue()
+		}
+		l.queueLock.Unlock()
+	}
+
+	return l.openFile()
+}
 
+func (l *Logger) openFile() error {
 	if l.file != nil {
 		return nil
 	}
@@ -73,6 +151,16 @@ func (l *Logger) Open() error {
 		return err
 	}
 	l.file = f
+	l.fileSize = 0
+	l.fileTime = time.Now()
+	l.fileRegular = false
+	if info, err := f.Stat(); err == nil {
+		l.fileSize = info.Size()
+		l.fileRegular = info.Mode().IsRegular()
+		if l.fileRegular && l.fileSize > 0 {
+			l.fileTime = fileStartTime(f, info)
+		}
+	}
 
 	return nil
 }
@@ -86,6 +174,17 @@ func (l *Logger) Reset() {
 	l.lock = sync.Mutex{}
 	l.Options = defaultLoggerOption()
 	l.Color = &tDefaultColor{}
+	l.Formatter = &TextFormatter{}
+	l.Rotation = RotatePolicy{}
+	l.Redaction = RedactPolicy{}
+	l.Async = AsyncOptions{}
+	atomic.StoreUint64(&l.dropped, 0)
+	l.levelLock.Lock()
+	l.stopLevelTimers()
+	l.sourceLevels = nil
+	l.levelPatterns = nil
+	l.sources = nil
+	l.levelLock.Unlock()
 	l.file = nil
 	l.opened = false
 }
@@ -93,6 +192,7 @@ func (l *Logger) Reset() {
 // Connect will prepare a new logtic source with the given name for this logging instance. Sources can be written
 // even if there is no open logtic log instance.
 func (l *Logger) Connect(sourceName string) *Source {
+	l.addSource(sourceName)
 	return &Source{
 		Name:     sourceName,
 		level:    nil,
@@ -100,17 +200,235 @@ func (l *Logger) Connect(sourceName string) *Source {
 	}
 }
 
-// Close will flush and close this logging instance.
+// SetSourceLevel will specify a log level for all sources matching the given name, ignoring the log level of this
+// instance. Unlike Source.OverrideLevel, this applies to sources that have not yet been connected, including sources
+// created by other packages. A level override on a specific source takes precedence over the level set here.
+//
+// The name may be an exact source name or a pattern, where '*' matches any number of characters and '?' matches a
+// single character. For example, "http*" matches all sources beginning with "http" and "*.db" matches all sources
+// ending with ".db". If multiple patterns match a source, an exact name is used first, otherwise the pattern with the
+// most literal characters is used.
+func (l *Logger) SetSourceLevel(name string, level LogLevel) {
+	l.levelLock.Lock()
+	defer l.levelLock.Unlock()
+	l.stopSourceLevelTimer(name)
+	l.setSourceLevel(name, level)
+}
+
+func (l *Logger) setSourceLevel(name string, level LogLevel) {
+	if l.sourceLevels == nil {
+		l.sourceLevels = map[string]LogLevel{}
+	}
+	if _, exists := l.sourceLevels[name]; !exists && isLevelPattern(name) {
+		l.levelPatterns = append(l.levelPatterns, name)
+	}
+	l.sourceLevels[name] = level
+}
+
+// ClearSourceLevel will clear the log level for the given source name or pattern, reverting back to the level of this
+// instance.
+func (l *Logger) ClearSourceLevel(name string) {
+	l.levelLock.Lock()
+	defer l.levelLock.Unlock()
+	l.stopSourceLevelTimer(name)
+	l.clearSourceLevel(name)
+}
+
+func (l *Logger) clearSourceLevel(name string) {
+	delete(l.sourceLevels, name)
+	for i, pattern := range l.levelPatterns {
+		if pattern == name {
+			l.levelPatterns = append(l.levelPatterns[:i:i], l.levelPatterns[i+1:]...)
+			break
+		}
+	}
+}
+
+// levelFor returns the level for the given source name, using the level of this instance if no source level is set
+func (l *Logger) levelFor(sourceName string) LogLevel {
+	l.levelLock.RLock()
+	defer l.levelLock.RUnlock()
+	if level, ok := l.sourceLevel(sourceName); ok {
+		return level
+	}
+	return l.Level
+}
+
+func (l *Logger) sourceLevel(sourceName string) (LogLevel, bool) {
+	if len(l.sourceLevels) == 0 {
+		return 0, false
+	}
+	if level, ok := l.sourceLevels[sourceName]; ok {
+		return level, true
+	}
+
+	bestLength := -1
+	var bestLevel LogLevel
+	for _, pattern := range l.levelPatterns {
+		if !matchLevelPattern(pattern, sourceName) {
+			continue
+		}
+		length := len(pattern) - strings.Count(pattern, "*") - strings.Count(pattern, "?")
+		if length > bestLength {
+			bestLength = length
+			bestLevel = l.sourceLevels[pattern]
+		}
+	}
+	return bestLevel, bestLength >= 0
+}
+
+func (l *Logger) addSource(name string) {
+	l.levelLock.Lock()
+	defer l.levelLock.Unlock()
+	if l.sources == nil {
+		l.sources = map[string]struct{}{}
+	}
+	l.sources[name] = struct{}{}
+}
+
+func isLevelPattern(name string) bool {
+	return strings.ContainsAny(name, "*?")
+}
+
+// matchLevelPattern reports whether name matches pattern, where '*' matches any number of characters and '?' matches
+// a single character
+func matchLevelPattern(pattern, name string) bool {
+	p, n := 0, 0
+	starP, starN := -1, 0
+	for n < len(name) {
+		if p < len(pattern) && (pattern[p] == '?' || pattern[p] == name[n]) {
+			p++
+			n++
+		} else if p < len(pattern) && pattern[p] == '*' {
+			starP = p
+			starN = n
+			p++
+		} else if starP >= 0 {
+			p = starP + 1
+			starN++
+			n = starN
+		} else {
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// Close will flush and close this logging instance. Any attached sinks that implement io.Closer are closed and all
+// sinks are detached. Close waits for any pending events to be written and for any rotated log files to finish
+// compressing.
 func (l *Logger) Close() {
+	l.queueLock.Lock()
+	queue := l.queue
+	l.queue = nil
+	l.queueLock.Unlock()
+	if queue != nil {
+		queue.close()
+	}
+
+	// The file is closed under the lock as it may be reopened concurrently, such as by HandleSignals
+	l.lock.Lock()
+	l.closeSinks()
+	l.closeFile()
+	l.lock.Unlock()
+
+	// Rotated files are only compressed when the file is rotated under the lock, so once the file is closed no more
+	// compression can start
+	l.compressing.Wait()
+}
+
+// Flush will wait for all pending events to be written and then commit the log file to disk. Any attached sinks that
+// implement Flusher are also flushed.
+func (l *Logger) Flush() {
+	l.queueLock.RLock()
+	queue := l.queue
+	l.queueLock.RUnlock()
+	if queue != nil {
+		queue.flush()
+	}
+
+	l.lock.Lock()
 	if l.file != nil {
 		l.file.Sync()
-		l.file.Close()
-		l.file = nil
 	}
+	l.lock.Unlock()
+
+	l.flushSinks()
 }
 
-func (l *Logger) write(message string) {
+// dispatch will write the event to the console, log file, and sinks; either in the background if asynchronous writes
+// are enabled or immediately. Fatal events are always written immediately once all pending events are written.
+func (l *Logger) dispatch(event Event) {
+	if event.Level == levelFatal {
+		l.Flush()
+		l.writeConsole(event)
+		l.write(event)
+		l.Flush()
+		return
+	}
+
+	l.queueLock.RLock()
+	queue := l.queue
+	l.queueLock.RUnlock()
+	if queue != nil && queue.push(event, &l.dropped) {
+		return
+	}
+
+	l.writeConsole(event)
+	l.write(event)
+}
+
+func (l *Logger) writeConsole(event Event) {
+	prefix := "[" + event.Level.String() + "][" + event.Source + "]"
+	if event.Caller != nil {
+		prefix += "[" + event.Caller.String() + "]"
+	}
+	message := event.Message
+	if event.Stack != "" {
+		message += indentStack(event.Stack)
+	}
+	journal, w := l.journalStdout, l.Stdout
+	if LevelError.allows(event.Level) {
+		journal, w = l.journalStderr, l.Stderr
+	}
+	if journal {
+		fmt.Fprintf(w, "<%d>%s %s\n", syslogSeverity(event.Level), prefix, message)
+		return
+	}
+
+	switch event.Level {
+	case LevelTrace, LevelDebug:
+		fmt.Fprintf(l.Stdout, "%s %s\n", l.colorHiBlack(prefix), message)
+	case LevelInfo:
+		fmt.Fprintf(l.Stdout, "%s %s\n", l.colorBlue(prefix), message)
+	case LevelNotice:
+		fmt.Fprintf(l.Stdout, "%s %s\n", l.colorCyan(prefix), message)
+	case LevelWarn:
+		fmt.Fprintf(l.Stdout, "%s %s\n", l.colorYellow(prefix), message)
+	case LevelCritical:
+		fmt.Fprintf(l.Stderr, "%s %s\n", l.colorMagenta(prefix), message)
+	default:
+		fmt.Fprintf(l.Stderr, "%s %s\n", l.colorRed(prefix), message)
+	}
+}
+
+func (l *Logger) write(event Event) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.file.WriteString(time.Now().Format(time.RFC3339) + " " + message + "\n")
+	if l.file != nil {
+		formatter := l.Formatter
+		if formatter == nil {
+			formatter = &TextFormatter{}
+		}
+		line := append(formatter.Format(event), '\n')
+		l.rotateIfNeeded(len(line))
+		if l.file != nil {
+			n, _ := l.file.Write(line)
+			l.fileSize += int64(n)
+		}
+	}
+	l.writeSinks(event)
 }
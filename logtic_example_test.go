@@ -71,6 +71,13 @@ func ExampleLogger_Connect() {
 	source2.Error("Something went wrong")
 }
 
+func ExampleSource_Trace() {
+	log := logtic.Log.Connect("Example")
+	log.Trace("This is a %s message", "trace")
+	// Terminal output: [TRACE][Example] This is a trace message
+	// File output: 2021-03-15T21:43:34-07:00 [TRACE][Example] This is a trace message
+}
+
 func ExampleSource_Debug() {
 	log := logtic.Log.Connect("Example")
 	log.Debug("This is a %s message", "debug")
@@ -85,6 +92,13 @@ func ExampleSource_Info() {
 	// File output: 2021-03-15T21:43:34-07:00 [INFO][Example] This is a info message
 }
 
+func ExampleSource_Notice() {
+	log := logtic.Log.Connect("Example")
+	log.Notice("This is a %s message", "notice")
+	// Terminal output: [NOTICE][Example] This is a notice message
+	// File output: 2021-03-15T21:43:34-07:00 [NOTICE][Example] This is a notice message
+}
+
 func ExampleSource_Warn() {
 	log := logtic.Log.Connect("Example")
 	log.Warn("This is a %s message", "warning")
@@ -99,6 +113,13 @@ func ExampleSource_Error() {
 	// File output: 2021-03-15T21:43:34-07:00 [ERROR][Example] This is a error message
 }
 
+func ExampleSource_Critical() {
+	log := logtic.Log.Connect("Example")
+	log.Critical("This is a %s message", "critical")
+	// Terminal output: [CRITICAL][Example] This is a critical message
+	// File output: 2021-03-15T21:43:34-07:00 [CRITICAL][Example] This is a critical message
+}
+
 func ExampleSource_Fatal() {
 	log := logtic.Log.Connect("Example")
 	log.Fatal("This is a %s message", "fatal")
@@ -210,3 +231,37 @@ func ExampleFormatBytesD() {
 	fmt.Println(logtic.FormatBytesD(10000000))
 	// output: 10.0 MB
 }
+
+// This example shows how to write each event in the log file as a JSON object
+func ExampleJSONFormatter() {
+	logtic.Log.FilePath = "./file.log"
+	logtic.Log.Formatter = &logtic.JSONFormatter{}
+
+	if err := logtic.Log.Open(); err != nil {
+		panic(err)
+	}
+
+	log := logtic.Log.Connect("Example")
+	log.PWarn("Warning event", map[string]any{
+		"param1": "string",
+		"param2": 123,
+	})
+	// File output: {"time":"2021-03-15T21:43:34-07:00","level":"WARN","source":"Example","message":"Warning event: param1='string' param2=123","event":"Warning event","parameters":{"param1":"string","param2":123}}
+}
+
+// This example shows how to bind parameters to a source so that they are included in every event
+func ExampleSource_With() {
+	log := logtic.Log.Connect("Example").With(map[string]any{
+		"request_id": 123,
+	})
+	log.Info("Request %s", "started")
+	// Terminal output: [INFO][Example] Request started: request_id=123
+	// File output: 2021-03-15T21:43:34-07:00 [INFO][Example] Request started: request_id=123
+}
+
+func ExampleSource_Sub() {
+	log := logtic.Log.Connect("Example").Sub("db")
+	log.Info("Connected")
+	// Terminal output: [INFO][Example/db] Connected
+	// File output: 2021-03-15T21:43:34-07:00 [INFO][Example/db] Connected
+}
@@ -0,0 +1,149 @@
+// Package logtictest provides helpers for testing the events written by logtic, without using a log file.
+//
+// A Recorder captures events from a logging instance in memory, which can then be inspected or asserted on. If the
+// test fails, all captured events are written to the test log.
+//
+//	func TestLogin(t *testing.T) {
+//	    recorder := logtictest.New(t, logtic.Log)
+//	    login("alice")
+//	    recorder.AssertLogged(t, logtic.LevelInfo, "auth", "Login")
+//	}
+package logtictest
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+// Recorder is a sink that captures events in memory
+type Recorder struct {
+	instance *logtic.Logger
+	events   []logtic.Event
+	lock     sync.Mutex
+}
+
+// New will attach a new recorder to the given logging instance, capturing all events that satisfy the level of the
+// instance or source. The instance must already be open. The recorder is detached when the test finishes and, if the
+// test failed, all captured events are written to the test log.
+func New(t testing.TB, instance *logtic.Logger) *Recorder {
+	t.Helper()
+
+	r := &Recorder{instance: instance}
+	instance.AddSink(r, logtic.LevelTrace)
+	t.Cleanup(func() {
+		instance.RemoveSink(r)
+		if t.Failed() {
+			r.dump(t)
+		}
+	})
+	return r
+}
+
+// NewLogger will create a new logging instance that captures every level without writing to the console or a log
+// file, and attach a recorder to it. The instance is closed when the test finishes.
+func NewLogger(t testing.TB) (*logtic.Logger, *Recorder) {
+	t.Helper()
+
+	instance := logtic.New()
+	instance.Level = logtic.LevelTrace
+	instance.Stdout = io.Discard
+	instance.Stderr = io.Discard
+	if err := instance.Open(); err != nil {
+		t.Fatalf("Error opening logging instance: %s", err.Error())
+	}
+	t.Cleanup(instance.Close)
+
+	return instance, New(t, instance)
+}
+
+// WriteEvent records the event
+func (r *Recorder) WriteEvent(event logtic.Event) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Events returns all captured events, after waiting for any pending events to be written
+func (r *Recorder) Events() []logtic.Event {
+	r.instance.Flush()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	events := make([]logtic.Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// Reset will discard all captured events
+func (r *Recorder) Reset() {
+	r.instance.Flush()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = nil
+}
+
+// Find returns all captured events with the given level and source, where the message contains substring. An empty
+// source matches events from any source.
+func (r *Recorder) Find(level logtic.LogLevel, source, substring string) []logtic.Event {
+	matches := []logtic.Event{}
+	for _, event := range r.Events() {
+		if event.Level != level {
+			continue
+		}
+		if source != "" && event.Source != source {
+			continue
+		}
+		if !strings.Contains(event.Message, substring) {
+			continue
+		}
+		matches = append(matches, event)
+	}
+	return matches
+}
+
+// AssertLogged will fail the test if no event was captured with the given level and source, where the message
+// contains substring. An empty source matches events from any source.
+func (r *Recorder) AssertLogged(t testing.TB, level logtic.LogLevel, source, substring string) {
+	t.Helper()
+	if len(r.Find(level, source, substring)) == 0 {
+		t.Errorf("Expected a %s level event from source '%s' containing '%s', but none was logged", level, sourceName(source), substring)
+	}
+}
+
+// AssertNotLogged will fail the test if any event was captured with the given level and source, where the message
+// contains substring. An empty source matches events from any source.
+func (r *Recorder) AssertNotLogged(t testing.TB, level logtic.LogLevel, source, substring string) {
+	t.Helper()
+	for _, event := range r.Find(level, source, substring) {
+		t.Errorf("Unexpected %s event from source '%s': %s", event.Level, event.Source, event.Message)
+	}
+}
+
+func (r *Recorder) dump(t testing.TB) {
+	events := r.Events()
+	if len(events) == 0 {
+		t.Logf("No log events were captured")
+		return
+	}
+
+	formatter := &logtic.TextFormatter{}
+	b := &strings.Builder{}
+	for _, event := range events {
+		b.Write(formatter.Format(event))
+		b.WriteByte('\n')
+	}
+	t.Logf("Captured %d log events:\n%s", len(events), b.String())
+}
+
+func sourceName(source string) string {
+	if source == "" {
+		return "*"
+	}
+	return source
+}
@@ -0,0 +1,122 @@
+package logtictest_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+	"github.com/ecnepsnai/logtic/logtictest"
+)
+
+// fakeT records failures and logs instead of failing the real test
+type fakeT struct {
+	testing.TB
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failed = true
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+}
+
+func (t *fakeT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Failed() bool {
+	return t.failed
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	instance, recorder := logtictest.NewLogger(t)
+
+	source := instance.Connect("test")
+	source.Debug("debug message")
+	source.PWarn("Warn event", map[string]any{"key": "value"})
+	instance.Connect("other").Info("info message")
+
+	events := recorder.Events()
+	if len(events) != 3 {
+		t.Fatalf("Unexpected number of events %d", len(events))
+	}
+	if events[1].Level != logtic.LevelWarn || events[1].Source != "test" || events[1].Parameters["key"] != "value" {
+		t.Errorf("Unexpected event: %+v", events[1])
+	}
+
+	recorder.AssertLogged(t, logtic.LevelDebug, "test", "debug")
+	recorder.AssertLogged(t, logtic.LevelWarn, "", "key='value'")
+	recorder.AssertNotLogged(t, logtic.LevelInfo, "test", "")
+	recorder.AssertNotLogged(t, logtic.LevelError, "", "")
+	if len(recorder.Find(logtic.LevelInfo, "", "message")) != 1 {
+		t.Errorf("Unexpected number of events found")
+	}
+
+	recorder.Reset()
+	if len(recorder.Events()) != 0 {
+		t.Errorf("Events not reset")
+	}
+}
+
+func TestRecorderAsync(t *testing.T) {
+	instance := logtic.New()
+	instance.Level = logtic.LevelInfo
+	instance.Async.Enabled = true
+	instance.Stdout = io.Discard
+	instance.Stderr = io.Discard
+	if err := instance.Open(); err != nil {
+		t.Fatalf("Error opening logging instance: %s", err.Error())
+	}
+	defer instance.Close()
+
+	recorder := logtictest.New(t, instance)
+	for i := 0; i < 100; i++ {
+		instance.Connect("test").Info("message %d", i)
+	}
+	recorder.AssertLogged(t, logtic.LevelInfo, "test", "message 99")
+}
+
+func TestRecorderFailure(t *testing.T) {
+	ft := &fakeT{}
+	instance, recorder := logtictest.NewLogger(ft)
+	instance.Connect("test").Info("info message")
+
+	recorder.AssertLogged(ft, logtic.LevelError, "test", "info")
+	if !ft.failed {
+		t.Errorf("AssertLogged did not fail")
+	}
+	recorder.AssertNotLogged(ft, logtic.LevelInfo, "", "info")
+
+	ft.finish()
+	if len(ft.logs) != 3 {
+		t.Fatalf("Unexpected test logs: %q", ft.logs)
+	}
+	if ft.logs[0] != "Expected a ERROR level event from source 'test' containing 'info', but none was logged" {
+		t.Errorf("Unexpected failure message '%s'", ft.logs[0])
+	}
+	if ft.logs[1] != "Unexpected INFO event from source 'test': info message" {
+		t.Errorf("Unexpected failure message '%s'", ft.logs[1])
+	}
+	if !strings.HasPrefix(ft.logs[2], "Captured 1 log events:\n") || !strings.Contains(ft.logs[2], "[INFO][test] info message\n") {
+		t.Errorf("Captured events not dumped: '%s'", ft.logs[2])
+	}
+}
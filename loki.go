@@ -0,0 +1,113 @@
+package logtic
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// LokiSink is a sink that pushes events to Grafana Loki using the JSON push API. Events are sent in batches in the
+// background, see BatchOptions and RetryPolicy.
+//
+// Each event is added to the stream with the static labels of the sink plus the "level" label, with the lowercase name
+// of the level, and the "source" label, with the name of the source. Parameters are not used as labels, as they often
+// have many unique values, instead they are included in the line which is formatted by the formatter of the sink.
+type LokiSink struct {
+	// Endpoint is the URL of the push API, such as "http://localhost:3100/loki/api/v1/push"
+	Endpoint string
+	// Labels are static labels added to every stream, such as the name of the application or host. Label names may only
+	// contain letters, digits, and underscores.
+	Labels map[string]string
+	// Formatter is used to format the line of each event. Defaults to JSONFormatter.
+	Formatter Formatter
+	// Headers are additional headers included with each request, such as X-Scope-OrgID for the tenant or
+	// Authorization
+	Headers map[string]string
+	// Client is the HTTP client used to send requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+	// Batch describes how events are batched
+	Batch BatchOptions
+	// Retry describes how failed requests are retried
+	Retry RetryPolicy
+
+	tBatchSink
+}
+
+// NewLokiSink will create a new Loki sink that pushes events to the Loki server at the given URL, such as
+// "http://localhost:3100", with the given static labels
+func NewLokiSink(url string, labels map[string]string) *LokiSink {
+	return &LokiSink{
+		Endpoint: strings.TrimRight(url, "/") + "/loki/api/v1/push",
+		Labels:   labels,
+	}
+}
+
+// WriteEvent adds the event to the next batch to be sent
+func (s *LokiSink) WriteEvent(event Event) error {
+	s.write(event, func() *tBatcher {
+		return newBatcher("Loki endpoint "+s.Endpoint, s.Batch, s.Retry, s.send)
+	})
+	return nil
+}
+
+type tLokiPush struct {
+	Streams []*tLokiStream `json:"streams"`
+}
+
+type tLokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (s *LokiSink) send(events []Event) error {
+	body, err := json.Marshal(s.push(events))
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	headers["Content-Type"] = "application/json"
+
+	_, _, err = postBatch(s.Client, s.Endpoint, headers, body)
+	return err
+}
+
+// push groups the events into streams by level and source, keeping the order of the events
+func (s *LokiSink) push(events []Event) tLokiPush {
+	formatter := s.Formatter
+	if formatter == nil {
+		formatter = &JSONFormatter{}
+	}
+
+	push := tLokiPush{}
+	streams := map[string]*tLokiStream{}
+	for _, event := range events {
+		level := strings.ToLower(event.Level.String())
+		key := level + "\x00" + event.Source
+		stream, ok := streams[key]
+		if !ok {
+			stream = &tLokiStream{Stream: s.labels(level, event.Source)}
+			streams[key] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(event.Time.UnixNano(), 10),
+			string(formatter.Format(event)),
+		})
+	}
+	return push
+}
+
+func (s *LokiSink) labels(level, source string) map[string]string {
+	labels := make(map[string]string, len(s.Labels)+2)
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+	labels["level"] = level
+	labels["source"] = source
+	return labels
+}
@@ -0,0 +1,167 @@
+package logtic_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func decodeLokiPush(t *testing.T, body []byte) lokiPush {
+	push := lokiPush{}
+	if err := json.Unmarshal(body, &push); err != nil {
+		t.Fatalf("Error decoding push request: %s", err.Error())
+	}
+	return push
+}
+
+func TestLokiSink(t *testing.T) {
+	collector := newTestCollector(t)
+
+	sink := logtic.NewLokiSink(collector.server.URL+"/", map[string]string{"app": "example"})
+	sink.Headers = map[string]string{"X-Scope-OrgID": "tenant1"}
+	for _, event := range testOTLPEvents() {
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	request := collector.requests[0]
+	if request.URL.Path != "/loki/api/v1/push" || request.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected request: %s %s", request.URL.Path, request.Header.Get("Content-Type"))
+	}
+	if request.Header.Get("X-Scope-OrgID") != "tenant1" {
+		t.Errorf("Missing tenant header")
+	}
+
+	push := decodeLokiPush(t, collector.bodies[0])
+	if len(push.Streams) != 2 {
+		t.Fatalf("Unexpected number of streams: %d", len(push.Streams))
+	}
+	labels := push.Streams[1].Stream
+	if len(labels) != 3 || labels["app"] != "example" || labels["level"] != "warn" || labels["source"] != "db" {
+		t.Errorf("Unexpected stream labels: %+v", labels)
+	}
+	value := push.Streams[1].Values[0]
+	if value[0] != "1700000000000000000" {
+		t.Errorf("Unexpected timestamp '%s'", value[0])
+	}
+
+	line := map[string]any{}
+	if err := json.Unmarshal([]byte(value[1]), &line); err != nil {
+		t.Fatalf("Error decoding line: %s", err.Error())
+	}
+	if line["event"] != "Slow query" || line["parameters"].(map[string]any)["table"] != "users" {
+		t.Errorf("Unexpected line: %s", value[1])
+	}
+}
+
+func TestLokiSinkFormatter(t *testing.T) {
+	collector := newTestCollector(t)
+
+	sink := logtic.NewLokiSink(collector.server.URL, nil)
+	sink.Formatter = &logtic.TextFormatter{}
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Close()
+
+	push := decodeLokiPush(t, collector.bodies[0])
+	if line := push.Streams[0].Values[0][1]; !strings.HasSuffix(line, "[INFO][http] Request finished") {
+		t.Errorf("Unexpected line '%s'", line)
+	}
+}
+
+func TestLokiSinkBatching(t *testing.T) {
+	collector := newTestCollector(t)
+
+	sink := logtic.NewLokiSink(collector.server.URL, nil)
+	sink.Batch = logtic.BatchOptions{MaxEvents: 2, Interval: 50 * time.Millisecond}
+	event := testOTLPEvents()[0]
+	for i := 0; i < 5; i++ {
+		sink.WriteEvent(event)
+	}
+
+	// The last event is sent once the interval has passed
+	deadline := time.Now().Add(5 * time.Second)
+	for collector.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if collector.count() != 3 {
+		t.Fatalf("Unexpected number of requests. Expected 3 got %d", collector.count())
+	}
+	total := 0
+	for _, body := range collector.bodies {
+		push := decodeLokiPush(t, body)
+		if n := len(push.Streams[0].Values); n > 2 {
+			t.Errorf("Unexpected number of events in batch: %d", n)
+		}
+		total += len(push.Streams[0].Values)
+	}
+	if total != 5 {
+		t.Errorf("Unexpected number of events sent. Expected 5 got %d", total)
+	}
+	sink.Close()
+}
+
+func TestLokiSinkRetry(t *testing.T) {
+	collector := newTestCollector(t, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadRequest)
+
+	sink := logtic.NewLokiSink(collector.server.URL, nil)
+	sink.Retry = logtic.RetryPolicy{InitialBackoff: time.Millisecond}
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Flush()
+
+	// Retried after 429 and 500 but not 400
+	if collector.count() != 3 {
+		t.Errorf("Unexpected number of requests. Expected 3 got %d", collector.count())
+	}
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Close()
+	if collector.count() != 4 {
+		t.Errorf("Unexpected number of requests. Expected 4 got %d", collector.count())
+	}
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+type panicFormatter struct{}
+
+func (panicFormatter) Format(event logtic.Event) []byte {
+	panic("formatter panic")
+}
+
+func TestLokiSinkPanic(t *testing.T) {
+	collector := newTestCollector(t)
+
+	// A batch that panics while being sent is dropped without stopping the sink
+	sink := logtic.NewLokiSink(collector.server.URL, nil)
+	sink.Formatter = panicFormatter{}
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Flush()
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+
+	sink.Formatter = &logtic.JSONFormatter{}
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Close()
+	if collector.count() != 1 {
+		t.Errorf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+}
@@ -0,0 +1,542 @@
+package logtic
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OTLPEncoding describes how an OTLP sink encodes requests
+type OTLPEncoding int
+
+const (
+	// OTLPProtobuf encodes requests using binary protobuf, with the content type application/x-protobuf
+	OTLPProtobuf = OTLPEncoding(0)
+	// OTLPJSON encodes requests using the OTLP JSON encoding, with the content type application/json
+	OTLPJSON = OTLPEncoding(1)
+)
+
+// OTLPSink is a sink that exports events as OpenTelemetry log records using OTLP/HTTP. Events are sent in batches in
+// the background, see BatchOptions and RetryPolicy.
+//
+// Each event is a log record with the severity based on the level of the event and the name of the source as the
+// instrumentation scope. The body is the message of the event, or the event name for parameterized events.
+// Parameters are included as attributes, along with the caller and stack using the OpenTelemetry semantic conventions
+// if they were captured. The trace context of the event is included if set.
+type OTLPSink struct {
+	// Endpoint is the URL of the OTLP/HTTP logs endpoint, such as "http://localhost:4318/v1/logs"
+	Endpoint string
+	// Encoding is the encoding of requests. Defaults to OTLPProtobuf.
+	Encoding OTLPEncoding
+	// Headers are additional headers included with each request, such as for authentication
+	Headers map[string]string
+	// ServiceName is the service.name resource attribute. Defaults to the name of the executable.
+	ServiceName string
+	// ResourceAttributes are additional attributes describing the resource that produced the events
+	ResourceAttributes map[string]any
+	// Client is the HTTP client used to send requests. Defaults to a client with a 30 second timeout.
+	Client *http.Client
+	// Batch describes how events are batched
+	Batch BatchOptions
+	// Retry describes how failed requests are retried
+	Retry RetryPolicy
+
+	tBatchSink
+}
+
+// NewOTLPSink will create a new OTLP sink that sends events to the given OTLP/HTTP logs endpoint, such as
+// "http://localhost:4318/v1/logs"
+func NewOTLPSink(endpoint string) *OTLPSink {
+	return &OTLPSink{
+		Endpoint:    endpoint,
+		ServiceName: filepath.Base(os.Args[0]),
+	}
+}
+
+// WriteEvent adds the event to the next batch to be sent
+func (s *OTLPSink) WriteEvent(event Event) error {
+	s.write(event, func() *tBatcher {
+		return newBatcher("OTLP endpoint "+s.Endpoint, s.Batch, s.Retry, s.send)
+	})
+	return nil
+}
+
+func (s *OTLPSink) send(events []Event) error {
+	request := s.request(events)
+
+	var body []byte
+	headers := map[string]string{}
+	for k, v := range s.Headers {
+		headers[k] = v
+	}
+	if s.Encoding == OTLPJSON {
+		data, err := json.Marshal(request.json())
+		if err != nil {
+			return err
+		}
+		body = data
+		headers["Content-Type"] = "application/json"
+	} else {
+		body = request.protobuf()
+		headers["Content-Type"] = "application/x-protobuf"
+	}
+
+	_, _, err := postBatch(s.Client, s.Endpoint, headers, body)
+	return err
+}
+
+// otlpSeverityNumber returns the OpenTelemetry severity number for the level
+func otlpSeverityNumber(level LogLevel) int {
+	switch level {
+	case LevelTrace:
+		return 1
+	case LevelDebug:
+		return 5
+	case LevelInfo:
+		return 9
+	case LevelNotice:
+		return 10
+	case LevelWarn:
+		return 13
+	case LevelError:
+		return 17
+	case LevelCritical:
+		return 19
+	case levelFatal:
+		return 21
+	}
+	return 0
+}
+
+// tOTLPRequest is an ExportLogsServiceRequest with a single resource
+type tOTLPRequest struct {
+	resource []tOTLPAttribute
+	scopes   []tOTLPScope
+}
+
+type tOTLPScope struct {
+	name    string
+	records []tOTLPRecord
+}
+
+type tOTLPRecord struct {
+	time           uint64
+	observedTime   uint64
+	severityNumber int
+	severityText   string
+	body           string
+	eventName      string
+	attributes     []tOTLPAttribute
+	traceID        []byte
+	spanID         []byte
+	flags          uint32
+}
+
+type tOTLPAttribute struct {
+	key   string
+	value tOTLPValue
+}
+
+// tOTLPValue is an AnyValue, where exactly one field is set based on kind
+type tOTLPValue struct {
+	kind        reflect.Kind
+	stringValue string
+	boolValue   bool
+	intValue    int64
+	doubleValue float64
+	bytesValue  []byte
+	arrayValue  []tOTLPValue
+	mapValue    []tOTLPAttribute
+}
+
+func (s *OTLPSink) request(events []Event) tOTLPRequest {
+	serviceName := s.ServiceName
+	if serviceName == "" {
+		serviceName = filepath.Base(os.Args[0])
+	}
+	request := tOTLPRequest{
+		resource: otlpAttributes(s.ResourceAttributes),
+	}
+	if _, ok := s.ResourceAttributes["service.name"]; !ok {
+		request.resource = append([]tOTLPAttribute{{key: "service.name", value: otlpValue(serviceName)}}, request.resource...)
+	}
+
+	// Group records by source, keeping the order of the events
+	scopeIndex := map[string]int{}
+	observed := uint64(time.Now().UnixNano())
+	for _, event := range events {
+		i, ok := scopeIndex[event.Source]
+		if !ok {
+			i = len(request.scopes)
+			scopeIndex[event.Source] = i
+			request.scopes = append(request.scopes, tOTLPScope{name: event.Source})
+		}
+		request.scopes[i].records = append(request.scopes[i].records, otlpRecord(event, observed))
+	}
+	return request
+}
+
+func otlpRecord(event Event, observed uint64) tOTLPRecord {
+	record := tOTLPRecord{
+		time:           uint64(event.Time.UnixNano()),
+		observedTime:   observed,
+		severityNumber: otlpSeverityNumber(event.Level),
+		severityText:   event.Level.String(),
+		body:           event.Message,
+		eventName:      event.Event,
+		attributes:     otlpAttributes(event.Parameters),
+	}
+	if event.Event != "" {
+		record.body = event.Event
+	}
+	if event.Caller != nil {
+		record.attributes = append(record.attributes,
+			tOTLPAttribute{key: "code.filepath", value: otlpValue(event.Caller.File)},
+			tOTLPAttribute{key: "code.lineno", value: otlpValue(event.Caller.Line)})
+		if event.Caller.Function != "" {
+			record.attributes = append(record.attributes,
+				tOTLPAttribute{key: "code.function", value: otlpValue(event.Caller.Function)})
+		}
+	}
+	if event.Stack != "" {
+		record.attributes = append(record.attributes,
+			tOTLPAttribute{key: "exception.stacktrace", value: otlpValue(event.Stack)})
+	}
+	if event.Trace != nil {
+		record.traceID, _ = hex.DecodeString(event.Trace.TraceID)
+		record.spanID, _ = hex.DecodeString(event.Trace.SpanID)
+		record.flags = uint32(event.Trace.TraceFlags)
+	}
+	return record
+}
+
+// otlpAttributes returns the parameters as attributes, sorted by key
+func otlpAttributes(parameters map[string]any) []tOTLPAttribute {
+	if len(parameters) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(parameters))
+	for k := range parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]tOTLPAttribute, len(keys))
+	for i, k := range keys {
+		attributes[i] = tOTLPAttribute{key: k, value: otlpValue(parameters[k])}
+	}
+	return attributes
+}
+
+// otlpValue returns the AnyValue for the parameter value v
+func otlpValue(v any) tOTLPValue {
+	switch t := v.(type) {
+	case nil:
+		return tOTLPValue{kind: reflect.Invalid}
+	case string:
+		return tOTLPValue{kind: reflect.String, stringValue: t}
+	case bool:
+		return tOTLPValue{kind: reflect.Bool, boolValue: t}
+	case []byte:
+		return tOTLPValue{kind: reflect.Uint8, bytesValue: t}
+	case time.Time:
+		return tOTLPValue{kind: reflect.String, stringValue: t.Format(time.RFC3339Nano)}
+	case error, fmt.Stringer:
+		// Formatted with fmt as it handles nil values, such as a nil pointer to an error type
+		return tOTLPValue{kind: reflect.String, stringValue: fmt.Sprintf("%v", t)}
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return tOTLPValue{kind: reflect.Int64, intValue: value.Int()}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return tOTLPValue{kind: reflect.String, stringValue: strconv.FormatUint(value.Uint(), 10)}
+		}
+		return tOTLPValue{kind: reflect.Int64, intValue: int64(value.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return tOTLPValue{kind: reflect.Float64, doubleValue: value.Float()}
+	case reflect.Slice, reflect.Array:
+		values := make([]tOTLPValue, value.Len())
+		for i := range values {
+			values[i] = otlpValue(value.Index(i).Interface())
+		}
+		return tOTLPValue{kind: reflect.Slice, arrayValue: values}
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			parameters := make(map[string]any, value.Len())
+			iter := value.MapRange()
+			for iter.Next() {
+				parameters[iter.Key().String()] = iter.Value().Interface()
+			}
+			return tOTLPValue{kind: reflect.Map, mapValue: otlpAttributes(parameters)}
+		}
+	}
+
+	s, _ := parameterString(v)
+	return tOTLPValue{kind: reflect.String, stringValue: s}
+}
+
+// JSON encoding, see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+func (r tOTLPRequest) json() map[string]any {
+	scopeLogs := make([]map[string]any, len(r.scopes))
+	for i, scope := range r.scopes {
+		records := make([]map[string]any, len(scope.records))
+		for j, record := range scope.records {
+			records[j] = record.json()
+		}
+		scopeLogs[i] = map[string]any{
+			"scope":      map[string]any{"name": scope.name},
+			"logRecords": records,
+		}
+	}
+
+	return map[string]any{
+		"resourceLogs": []map[string]any{
+			{
+				"resource":  map[string]any{"attributes": otlpAttributesJSON(r.resource)},
+				"scopeLogs": scopeLogs,
+			},
+		},
+	}
+}
+
+func (r tOTLPRecord) json() map[string]any {
+	record := map[string]any{
+		"timeUnixNano":         strconv.FormatUint(r.time, 10),
+		"observedTimeUnixNano": strconv.FormatUint(r.observedTime, 10),
+		"severityNumber":       r.severityNumber,
+		"severityText":         r.severityText,
+		"body":                 map[string]any{"stringValue": r.body},
+	}
+	if r.eventName != "" {
+		record["eventName"] = r.eventName
+	}
+	if len(r.attributes) > 0 {
+		record["attributes"] = otlpAttributesJSON(r.attributes)
+	}
+	if len(r.traceID) > 0 {
+		record["traceId"] = hex.EncodeToString(r.traceID)
+		record["spanId"] = hex.EncodeToString(r.spanID)
+		record["flags"] = r.flags
+	}
+	return record
+}
+
+func otlpAttributesJSON(attributes []tOTLPAttribute) []map[string]any {
+	out := make([]map[string]any, len(attributes))
+	for i, attribute := range attributes {
+		out[i] = map[string]any{
+			"key":   attribute.key,
+			"value": attribute.value.json(),
+		}
+	}
+	return out
+}
+
+func (v tOTLPValue) json() map[string]any {
+	switch v.kind {
+	case reflect.String:
+		return map[string]any{"stringValue": v.stringValue}
+	case reflect.Bool:
+		return map[string]any{"boolValue": v.boolValue}
+	case reflect.Int64:
+		return map[string]any{"intValue": strconv.FormatInt(v.intValue, 10)}
+	case reflect.Float64:
+		if math.IsNaN(v.doubleValue) || math.IsInf(v.doubleValue, 0) {
+			return map[string]any{"stringValue": strconv.FormatFloat(v.doubleValue, 'f', -1, 64)}
+		}
+		return map[string]any{"doubleValue": v.doubleValue}
+	case reflect.Uint8:
+		return map[string]any{"bytesValue": base64.StdEncoding.EncodeToString(v.bytesValue)}
+	case reflect.Slice:
+		values := make([]map[string]any, len(v.arrayValue))
+		for i, value := range v.arrayValue {
+			values[i] = value.json()
+		}
+		return map[string]any{"arrayValue": map[string]any{"values": values}}
+	case reflect.Map:
+		return map[string]any{"kvlistValue": map[string]any{"values": otlpAttributesJSON(v.mapValue)}}
+	}
+	return map[string]any{}
+}
+
+// Protobuf encoding, using the field numbers from opentelemetry/proto/logs/v1/logs.proto and
+// opentelemetry/proto/common/v1/common.proto
+
+func (r tOTLPRequest) protobuf() []byte {
+	// ExportLogsServiceRequest
+	w := &tProtoWriter{}
+	w.message(1, func(w *tProtoWriter) { // ResourceLogs
+		w.message(1, func(w *tProtoWriter) { // Resource
+			for _, attribute := range r.resource {
+				w.message(1, attribute.protobuf)
+			}
+		})
+		for _, scope := range r.scopes {
+			scope := scope
+			w.message(2, func(w *tProtoWriter) { // ScopeLogs
+				w.message(1, func(w *tProtoWriter) { // InstrumentationScope
+					w.string(1, scope.name)
+				})
+				for _, record := range scope.records {
+					w.message(2, record.protobuf)
+				}
+			})
+		}
+	})
+	return w.b
+}
+
+func (r tOTLPRecord) protobuf(w *tProtoWriter) {
+	w.fixed64(1, r.time)
+	w.varint(2, uint64(r.severityNumber))
+	w.string(3, r.severityText)
+	w.message(5, otlpValue(r.body).protobuf)
+	for _, attribute := range r.attributes {
+		w.message(6, attribute.protobuf)
+	}
+	if len(r.traceID) > 0 {
+		w.fixed32(8, r.flags)
+		w.bytes(9, r.traceID)
+		w.bytes(10, r.spanID)
+	}
+	w.fixed64(11, r.observedTime)
+	w.string(12, r.eventName)
+}
+
+func (a tOTLPAttribute) protobuf(w *tProtoWriter) {
+	w.string(1, a.key)
+	w.message(2, a.value.protobuf)
+}
+
+func (v tOTLPValue) protobuf(w *tProtoWriter) {
+	switch v.kind {
+	case reflect.String:
+		w.forceString(1, v.stringValue)
+	case reflect.Bool:
+		w.forceVarint(2, boolToUint(v.boolValue))
+	case reflect.Int64:
+		w.forceVarint(3, uint64(v.intValue))
+	case reflect.Float64:
+		w.tag(4, 1)
+		w.b = appendFixed64(w.b, math.Float64bits(v.doubleValue))
+	case reflect.Uint8:
+		w.tag(7, 2)
+		w.b = appendUvarint(w.b, uint64(len(v.bytesValue)))
+		w.b = append(w.b, v.bytesValue...)
+	case reflect.Slice:
+		w.message(5, func(w *tProtoWriter) { // ArrayValue
+			for _, value := range v.arrayValue {
+				w.message(1, value.protobuf)
+			}
+		})
+	case reflect.Map:
+		w.message(6, func(w *tProtoWriter) { // KeyValueList
+			for _, attribute := range v.mapValue {
+				w.message(1, attribute.protobuf)
+			}
+		})
+	}
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// tProtoWriter writes protobuf wire format. Fields with a default value are omitted, except for the forced variants
+// which are used for fields of a oneof.
+type tProtoWriter struct {
+	b []byte
+}
+
+func (w *tProtoWriter) tag(field int, wireType int) {
+	w.b = appendUvarint(w.b, uint64(field)<<3|uint64(wireType))
+}
+
+func (w *tProtoWriter) varint(field int, v uint64) {
+	if v != 0 {
+		w.forceVarint(field, v)
+	}
+}
+
+func (w *tProtoWriter) forceVarint(field int, v uint64) {
+	w.tag(field, 0)
+	w.b = appendUvarint(w.b, v)
+}
+
+func (w *tProtoWriter) fixed64(field int, v uint64) {
+	if v != 0 {
+		w.tag(field, 1)
+		w.b = appendFixed64(w.b, v)
+	}
+}
+
+func (w *tProtoWriter) fixed32(field int, v uint32) {
+	if v != 0 {
+		w.tag(field, 5)
+		w.b = appendFixed32(w.b, v)
+	}
+}
+
+func (w *tProtoWriter) bytes(field int, v []byte) {
+	if len(v) > 0 {
+		w.tag(field, 2)
+		w.b = appendUvarint(w.b, uint64(len(v)))
+		w.b = append(w.b, v...)
+	}
+}
+
+func (w *tProtoWriter) string(field int, v string) {
+	if v != "" {
+		w.forceString(field, v)
+	}
+}
+
+func (w *tProtoWriter) forceString(field int, v string) {
+	w.tag(field, 2)
+	w.b = appendUvarint(w.b, uint64(len(v)))
+	w.b = append(w.b, v...)
+}
+
+// message writes an embedded message, written by f
+func (w *tProtoWriter) message(field int, f func(w *tProtoWriter)) {
+	inner := &tProtoWriter{}
+	f(inner)
+	w.tag(field, 2)
+	w.b = appendUvarint(w.b, uint64(len(inner.b)))
+	w.b = append(w.b, inner.b...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendFixed32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
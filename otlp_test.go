@@ -0,0 +1,506 @@
+package logtic_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+// testCollector is a fake HTTP server that records each request and responds with the given status codes, followed
+// by 200 OK
+type testCollector struct {
+	server   *httptest.Server
+	lock     sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	status   []int
+}
+
+func newTestCollector(t *testing.T, status ...int) *testCollector {
+	c := &testCollector{status: status}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.lock.Lock()
+		c.requests = append(c.requests, r)
+		c.bodies = append(c.bodies, body)
+		code := http.StatusOK
+		if len(c.status) > 0 {
+			code = c.status[0]
+			c.status = c.status[1:]
+		}
+		c.lock.Unlock()
+		if code == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *testCollector) count() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.bodies)
+}
+
+func testOTLPEvents() []logtic.Event {
+	now := time.Unix(1700000000, 0)
+	return []logtic.Event{
+		{
+			Time:    now,
+			Level:   logtic.LevelInfo,
+			Source:  "http",
+			Message: "Request finished",
+		},
+		{
+			Time:       now,
+			Level:      logtic.LevelWarn,
+			Source:     "db",
+			Message:    "Slow query: duration=1500 table='users'",
+			Event:      "Slow query",
+			Parameters: map[string]any{"duration": 1500, "table": "users", "cached": false, "ratio": 0.5},
+			Caller:     &logtic.Caller{File: "/src/db.go", Line: 42, Function: "example.com/db.Query"},
+			Trace: &logtic.TraceContext{
+				TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:     "00f067aa0ba902b7",
+				TraceFlags: 1,
+			},
+		},
+	}
+}
+
+func TestOTLPSinkJSON(t *testing.T) {
+	collector := newTestCollector(t)
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.Encoding = logtic.OTLPJSON
+	sink.ServiceName = "example"
+	sink.Headers = map[string]string{"Authorization": "Bearer secret"}
+	for _, event := range testOTLPEvents() {
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	request := collector.requests[0]
+	if request.URL.Path != "/v1/logs" || request.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected request: %s %s", request.URL.Path, request.Header.Get("Content-Type"))
+	}
+	if request.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Missing authorization header")
+	}
+
+	type anyValue struct {
+		StringValue *string  `json:"stringValue"`
+		BoolValue   *bool    `json:"boolValue"`
+		IntValue    *string  `json:"intValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+	}
+	type keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	body := struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []keyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				Scope struct {
+					Name string `json:"name"`
+				} `json:"scope"`
+				LogRecords []struct {
+					TimeUnixNano   string     `json:"timeUnixNano"`
+					SeverityNumber int        `json:"severityNumber"`
+					SeverityText   string     `json:"severityText"`
+					Body           anyValue   `json:"body"`
+					Attributes     []keyValue `json:"attributes"`
+					TraceID        string     `json:"traceId"`
+					SpanID         string     `json:"spanId"`
+					Flags          int        `json:"flags"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}{}
+	if err := json.Unmarshal(collector.bodies[0], &body); err != nil {
+		t.Fatalf("Error decoding request: %s", err.Error())
+	}
+
+	if len(body.ResourceLogs) != 1 {
+		t.Fatalf("Unexpected number of resource logs: %d", len(body.ResourceLogs))
+	}
+	resource := body.ResourceLogs[0]
+	if len(resource.Resource.Attributes) != 1 || resource.Resource.Attributes[0].Key != "service.name" ||
+		*resource.Resource.Attributes[0].Value.StringValue != "example" {
+		t.Errorf("Unexpected resource attributes: %+v", resource.Resource.Attributes)
+	}
+	if len(resource.ScopeLogs) != 2 || resource.ScopeLogs[0].Scope.Name != "http" || resource.ScopeLogs[1].Scope.Name != "db" {
+		t.Fatalf("Unexpected scope logs: %+v", resource.ScopeLogs)
+	}
+
+	record := resource.ScopeLogs[0].LogRecords[0]
+	if record.SeverityNumber != 9 || record.SeverityText != "INFO" || *record.Body.StringValue != "Request finished" {
+		t.Errorf("Unexpected log record: %+v", record)
+	}
+	if record.TimeUnixNano != "1700000000000000000" {
+		t.Errorf("Unexpected time '%s'", record.TimeUnixNano)
+	}
+
+	record = resource.ScopeLogs[1].LogRecords[0]
+	if record.SeverityNumber != 13 || record.SeverityText != "WARN" || *record.Body.StringValue != "Slow query" {
+		t.Errorf("Unexpected log record: %+v", record)
+	}
+	if record.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || record.SpanID != "00f067aa0ba902b7" || record.Flags != 1 {
+		t.Errorf("Unexpected trace context: %s %s %d", record.TraceID, record.SpanID, record.Flags)
+	}
+	attributes := map[string]anyValue{}
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	if v := attributes["duration"].IntValue; v == nil || *v != "1500" {
+		t.Errorf("Unexpected duration attribute: %+v", attributes["duration"])
+	}
+	if v := attributes["table"].StringValue; v == nil || *v != "users" {
+		t.Errorf("Unexpected table attribute: %+v", attributes["table"])
+	}
+	if v := attributes["cached"].BoolValue; v == nil || *v {
+		t.Errorf("Unexpected cached attribute: %+v", attributes["cached"])
+	}
+	if v := attributes["ratio"].DoubleValue; v == nil || *v != 0.5 {
+		t.Errorf("Unexpected ratio attribute: %+v", attributes["ratio"])
+	}
+	if v := attributes["code.lineno"].IntValue; v == nil || *v != "42" {
+		t.Errorf("Unexpected code.lineno attribute: %+v", attributes["code.lineno"])
+	}
+	if v := attributes["code.function"].StringValue; v == nil || *v != "example.com/db.Query" {
+		t.Errorf("Unexpected code.function attribute: %+v", attributes["code.function"])
+	}
+}
+
+// protoFields decodes the top level fields of a protobuf message. Varint and fixed fields are returned as the value
+// encoded in 8 little endian bytes.
+func protoFields(t *testing.T, b []byte) map[int][][]byte {
+	fields := map[int][][]byte{}
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("Invalid protobuf tag")
+		}
+		b = b[n:]
+		field := int(tag >> 3)
+		var value []byte
+		switch tag & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			value = make([]byte, 8)
+			binary.LittleEndian.PutUint64(value, v)
+			b = b[n:]
+		case 1:
+			value, b = b[:8], b[8:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			b = b[n:]
+			value, b = b[:length], b[length:]
+		case 5:
+			value = append(b[:4:4], 0, 0, 0, 0)
+			b = b[4:]
+		default:
+			t.Fatalf("Unexpected protobuf wire type %d", tag&7)
+		}
+		fields[field] = append(fields[field], value)
+	}
+	return fields
+}
+
+func TestOTLPSinkProtobuf(t *testing.T) {
+	collector := newTestCollector(t)
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.ServiceName = "example"
+	for _, event := range testOTLPEvents() {
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	if contentType := collector.requests[0].Header.Get("Content-Type"); contentType != "application/x-protobuf" {
+		t.Errorf("Unexpected content type '%s'", contentType)
+	}
+
+	resourceLogs := protoFields(t, protoFields(t, collector.bodies[0])[1][0])
+	resource := protoFields(t, resourceLogs[1][0])
+	serviceName := protoFields(t, resource[1][0])
+	if string(serviceName[1][0]) != "service.name" || string(protoFields(t, serviceName[2][0])[1][0]) != "example" {
+		t.Errorf("Unexpected resource attribute")
+	}
+
+	scopeLogs := resourceLogs[2]
+	if len(scopeLogs) != 2 {
+		t.Fatalf("Unexpected number of scope logs: %d", len(scopeLogs))
+	}
+	scope := protoFields(t, scopeLogs[1])
+	if name := string(protoFields(t, scope[1][0])[1][0]); name != "db" {
+		t.Errorf("Unexpected scope name '%s'", name)
+	}
+
+	record := protoFields(t, scope[2][0])
+	if v := binary.LittleEndian.Uint64(record[1][0]); v != 1700000000000000000 {
+		t.Errorf("Unexpected time %d", v)
+	}
+	if v := binary.LittleEndian.Uint64(record[2][0]); v != 13 {
+		t.Errorf("Unexpected severity number %d", v)
+	}
+	if v := string(record[3][0]); v != "WARN" {
+		t.Errorf("Unexpected severity text '%s'", v)
+	}
+	if v := string(protoFields(t, record[5][0])[1][0]); v != "Slow query" {
+		t.Errorf("Unexpected body '%s'", v)
+	}
+	if v := binary.LittleEndian.Uint64(record[8][0]); v != 1 {
+		t.Errorf("Unexpected flags %d", v)
+	}
+	if len(record[9][0]) != 16 || len(record[10][0]) != 8 {
+		t.Errorf("Unexpected trace ID or span ID length")
+	}
+
+	attributes := map[string]map[int][][]byte{}
+	for _, attribute := range record[6] {
+		kv := protoFields(t, attribute)
+		attributes[string(kv[1][0])] = protoFields(t, kv[2][0])
+	}
+	if v := binary.LittleEndian.Uint64(attributes["duration"][3][0]); v != 1500 {
+		t.Errorf("Unexpected duration attribute %d", v)
+	}
+	if v := string(attributes["table"][1][0]); v != "users" {
+		t.Errorf("Unexpected table attribute '%s'", v)
+	}
+	if v := binary.LittleEndian.Uint64(attributes["cached"][2][0]); v != 0 {
+		t.Errorf("Unexpected cached attribute %d", v)
+	}
+}
+
+func TestOTLPSinkRetry(t *testing.T) {
+	collector := newTestCollector(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.Retry = logtic.RetryPolicy{InitialBackoff: time.Millisecond}
+	sink.WriteEvent(testOTLPEvents()[0])
+	sink.Flush()
+
+	if collector.count() != 3 {
+		t.Errorf("Unexpected number of requests. Expected 3 got %d", collector.count())
+	}
+	if dropped := sink.DroppedEvents(); dropped != 0 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+	sink.Close()
+}
+
+func TestOTLPSinkPermanentFailure(t *testing.T) {
+	collector := newTestCollector(t, http.StatusBadRequest)
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.Retry = logtic.RetryPolicy{InitialBackoff: time.Millisecond}
+	for _, event := range testOTLPEvents() {
+		sink.WriteEvent(event)
+	}
+	sink.Close()
+
+	if collector.count() != 1 {
+		t.Errorf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	if dropped := sink.DroppedEvents(); dropped != 2 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestOTLPSinkBatching(t *testing.T) {
+	var requests int32
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-block
+	}))
+	defer server.Close()
+
+	sink := logtic.NewOTLPSink(server.URL + "/v1/logs")
+	sink.Batch = logtic.BatchOptions{MaxEvents: 2, QueueSize: 4, Overflow: logtic.OverflowDropNewest}
+	event := testOTLPEvents()[0]
+	sink.WriteEvent(event)
+	sink.WriteEvent(event)
+
+	// Wait for the first batch to be sent, then fill the queue while the request is blocked
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 6; i++ {
+		sink.WriteEvent(event)
+	}
+	if dropped := sink.DroppedEvents(); dropped != 2 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+
+	close(block)
+	sink.Close()
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("Unexpected number of requests. Expected 3 got %d", n)
+	}
+}
+
+func TestOTLPSinkLogger(t *testing.T) {
+	Setup()
+	collector := newTestCollector(t)
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	logtic.Log.AddSink(sink, logtic.LevelInfo)
+	source := logtic.Log.Connect("otlp")
+	source.Debug("Not sent")
+	source.Info("Sent")
+	source.Error("Also sent")
+	logtic.Log.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	record := protoFields(t, protoFields(t, protoFields(t, protoFields(t, collector.bodies[0])[1][0])[2][0])[2][0])
+	if v := string(protoFields(t, record[5][0])[1][0]); v != "Sent" {
+		t.Errorf("Unexpected body '%s'", v)
+	}
+}
+
+func TestOTLPSinkReusedParameters(t *testing.T) {
+	Setup()
+	collector := newTestCollector(t)
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.Encoding = logtic.OTLPJSON
+	logtic.Log.AddSink(sink, logtic.LevelInfo)
+	source := logtic.Log.Connect("otlp")
+
+	// The map is changed after each event is written, which must not affect events waiting to be sent
+	parameters := map[string]any{}
+	for i := 0; i < 100; i++ {
+		parameters["i"] = i
+		source.PInfo("Count", parameters)
+	}
+	logtic.Log.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	body := string(collector.bodies[0])
+	if !strings.Contains(body, `"intValue":"0"`) || !strings.Contains(body, `"intValue":"99"`) {
+		t.Errorf("Unexpected request: %s", body)
+	}
+}
+
+func TestOTLPSinkNilError(t *testing.T) {
+	Setup()
+	collector := newTestCollector(t)
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	logtic.Log.AddSink(sink, logtic.LevelInfo)
+	logtic.Log.Connect("otlp").PError("Query", map[string]any{"error": (*queryError)(nil)})
+	logtic.Log.Close()
+
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+	if dropped := sink.DroppedEvents(); dropped != 0 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestOTLPSinkCloseDuringBackoff(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := logtic.NewOTLPSink(server.URL + "/v1/logs")
+	sink.Batch = logtic.BatchOptions{Interval: 10 * time.Millisecond}
+	sink.Retry = logtic.RetryPolicy{MaxBackoff: time.Minute}
+	sink.WriteEvent(testOTLPEvents()[0])
+
+	// Wait for the first request to fail, then close while the sink waits to retry
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	start := time.Now()
+	sink.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close took %s", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("Unexpected number of requests. Expected 2 got %d", n)
+	}
+	if dropped := sink.DroppedEvents(); dropped != 1 {
+		t.Errorf("Unexpected number of dropped events: %d", dropped)
+	}
+}
+
+func TestOTLPSinkFlushedOnPanic(t *testing.T) {
+	Setup()
+	collector := newTestCollector(t)
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	sink := logtic.NewOTLPSink(collector.server.URL + "/v1/logs")
+	sink.Batch = logtic.BatchOptions{Interval: time.Hour}
+	logtic.Log.AddSink(sink, logtic.LevelInfo)
+	source := logtic.Log.Connect("otlp")
+
+	// The fatal event is exported before the panic, without waiting for the interval
+	func() {
+		defer recoverPanic()
+		source.Panic("Something went wrong")
+	}()
+	if collector.count() != 1 {
+		t.Fatalf("Unexpected number of requests. Expected 1 got %d", collector.count())
+	}
+
+	source.Info("Flushed")
+	logtic.Log.Flush()
+	if collector.count() != 2 {
+		t.Fatalf("Unexpected number of requests. Expected 2 got %d", collector.count())
+	}
+}
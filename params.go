@@ -2,6 +2,7 @@ package logtic
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"sort"
 	"time"
@@ -25,48 +26,11 @@ func StringFromParameters(parameters map[string]any) string {
 	}
 	sort.Strings(keys)
 	for i, k := range keys {
-		v := parameters[k]
-		t := reflect.TypeOf(v)
-		out += k + "="
-		if t == nil {
-			out += "nil"
-		} else {
-			switch t.Kind() {
-			case reflect.String:
-				if t.AssignableTo(reflect.TypeOf("")) {
-					out += "'" + v.(string) + "'"
-				} else {
-					out += fmt.Sprintf("'%v'", v)
-				}
-			case reflect.Int,
-				reflect.Int8,
-				reflect.Int16,
-				reflect.Int32,
-				reflect.Int64,
-				reflect.Uint,
-				reflect.Uint8,
-				reflect.Uint16,
-				reflect.Uint32,
-				reflect.Uint64:
-				out += fmt.Sprintf("%d", v)
-			case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
-				out += fmt.Sprintf("%f", v)
-			case reflect.Slice, reflect.Array:
-				if b, isBytes := v.([]byte); isBytes {
-					out += fmt.Sprintf("%x", b)
-				} else {
-					out += fmt.Sprintf("'%v'", v)
-				}
-			case reflect.Struct:
-				if t, isTime := v.(time.Time); isTime {
-					out += "'" + t.Format(time.RFC3339) + "'"
-				} else {
-					out += fmt.Sprintf("'%v'", v)
-				}
-			default:
-				out += fmt.Sprintf("'%v'", v)
-			}
+		value, quoted := parameterString(parameters[k])
+		if quoted {
+			value = "'" + value + "'"
 		}
+		out += k + "=" + value
 		if i != last {
 			out += " "
 		}
@@ -74,6 +38,47 @@ func StringFromParameters(parameters map[string]any) string {
 	return out
 }
 
+// parameterString returns the string representation of the parameter value v and if it should be wrapped in quotes
+func parameterString(v any) (string, bool) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "nil", false
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if t.AssignableTo(reflect.TypeOf("")) {
+			return v.(string), true
+		}
+		return fmt.Sprintf("%v", v), true
+	case reflect.Int,
+		reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64,
+		reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64:
+		return fmt.Sprintf("%d", v), false
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return fmt.Sprintf("%f", v), false
+	case reflect.Slice, reflect.Array:
+		if b, isBytes := v.([]byte); isBytes {
+			return fmt.Sprintf("%x", b), false
+		}
+		return fmt.Sprintf("%v", v), true
+	case reflect.Struct:
+		if t, isTime := v.(time.Time); isTime {
+			return t.Format(time.RFC3339), true
+		}
+		return fmt.Sprintf("%v", v), true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
+
 // FormatBytesB takes in a number of bytes and returns a human readable string with binary units (up-to Exbibyte)
 func FormatBytesB(b uint64) string {
 	const unit = 1024
@@ -102,12 +107,20 @@ func FormatBytesD(b uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
 }
 
+// PTrace will log a trace parameterized message.
+// Parameterized messages are formatted as key=value strings. Depending on the type of the parameter value, it may
+// be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
+// sorted in the outputted string.
+func (s *Source) PTrace(event string, parameters map[string]any) {
+	s.plog(LevelTrace, event, parameters)
+}
+
 // PDebug will log a debug parameterized message.
 // Parameterized messages are formatted as key=value strings. Depending on the type of the parameter value, it may
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PDebug(event string, parameters map[string]any) {
-	s.Debug("%s: %s", event, StringFromParameters(parameters))
+	s.plog(LevelDebug, event, parameters)
 }
 
 // PInfo will log an informational parameterized message.
@@ -115,7 +128,15 @@ func (s *Source) PDebug(event string, parameters map[string]any) {
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PInfo(event string, parameters map[string]any) {
-	s.Info("%s: %s", event, StringFromParameters(parameters))
+	s.plog(LevelInfo, event, parameters)
+}
+
+// PNotice will log a notice parameterized message.
+// Parameterized messages are formatted as key=value strings. Depending on the type of the parameter value, it may
+// be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
+// sorted in the outputted string.
+func (s *Source) PNotice(event string, parameters map[string]any) {
+	s.plog(LevelNotice, event, parameters)
 }
 
 // PWarn will log a warning parameterized message.
@@ -123,7 +144,7 @@ func (s *Source) PInfo(event string, parameters map[string]any) {
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PWarn(event string, parameters map[string]any) {
-	s.Warn("%s: %s", event, StringFromParameters(parameters))
+	s.plog(LevelWarn, event, parameters)
 }
 
 // PError will log an error parameterized message. Errors are printed to stderr.
@@ -131,7 +152,15 @@ func (s *Source) PWarn(event string, parameters map[string]any) {
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PError(event string, parameters map[string]any) {
-	s.Error("%s: %s", event, StringFromParameters(parameters))
+	s.plog(LevelError, event, parameters)
+}
+
+// PCritical will log a critical parameterized message. Critical messages are printed to stderr.
+// Parameterized messages are formatted as key=value strings. Depending on the type of the parameter value, it may
+// be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
+// sorted in the outputted string.
+func (s *Source) PCritical(event string, parameters map[string]any) {
+	s.plog(LevelCritical, event, parameters)
 }
 
 // PFatal will log a fatal parameterized error message and exit the application with status 1.
@@ -140,7 +169,8 @@ func (s *Source) PError(event string, parameters map[string]any) {
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PFatal(event string, parameters map[string]any) {
-	s.Fatal("%s: %s", event, StringFromParameters(parameters))
+	s.write(s.newParameterizedEvent(levelFatal, event, parameters))
+	os.Exit(1)
 }
 
 // PPanic functions like source.PFatal() but panics rather than exits.
@@ -148,7 +178,9 @@ func (s *Source) PFatal(event string, parameters map[string]any) {
 // be wrapped in single quotes. Byte slices are represented as hexadecimal strings. Parameters are always alphabetically
 // sorted in the outputted string.
 func (s *Source) PPanic(event string, parameters map[string]any) {
-	s.Panic("%s: %s", event, StringFromParameters(parameters))
+	e := s.newParameterizedEvent(levelFatal, event, parameters)
+	s.write(e)
+	panic(e.Message)
 }
 
 // PWrite will call the matching write function for the given level, printing the provided message.
@@ -160,17 +192,9 @@ func (s *Source) PPanic(event string, parameters map[string]any) {
 //
 //	source.PDebug("My Event", map[string]any{"key": "value"})
 func (s *Source) PWrite(level LogLevel, event string, parameters map[string]any) {
-	defer panicRecover()
-
 	switch level {
-	case LevelDebug:
-		s.PDebug(event, parameters)
-	case LevelInfo:
-		s.PInfo(event, parameters)
-	case LevelWarn:
-		s.PWarn(event, parameters)
-	case LevelError:
-		s.PError(event, parameters)
+	case LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelCritical:
+		s.plog(level, event, parameters)
 	default:
 		return
 	}
@@ -0,0 +1,222 @@
+package logtic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// redacted is the replacement for redacted values
+const redacted = "[REDACTED]"
+
+// RedactPolicy describes which parameters and parts of messages are redacted by a logging instance. Redaction happens
+// before events are written anywhere, including sinks.
+type RedactPolicy struct {
+	// Keys are the parameter keys whose values are redacted. Keys are matched without case and may be a pattern, where
+	// '*' matches any number of characters and '?' matches a single character. For example, "*token*" matches
+	// "access_token" and "TokenID". Parameter keys containing dots are also matched using the part after the last dot,
+	// so "password" matches "error.password".
+	Keys []string
+	// KeyExpressions are regular expressions for parameter keys whose values are redacted. Like Keys, they are also
+	// matched against the part of the key after the last dot.
+	KeyExpressions []*regexp.Regexp
+	// Hash replaces redacted parameter values with a truncated SHA-256 hash of the value rather than "[REDACTED]", so
+	// that events with the same value can be correlated. Hashes of values that are easily guessed, such as short
+	// passwords, can be reversed.
+	Hash bool
+	// MessageExpressions are regular expressions that are redacted from the message of every event, including any
+	// rendered parameters, and from the values of string, error, and fmt.Stringer parameters. If the expression has a
+	// capture group then only the first group is redacted, otherwise the entire match is redacted.
+	MessageExpressions []*regexp.Regexp
+}
+
+// cardNumberExpression matches possible payment card numbers. Matches are only redacted if they pass the Luhn check, so
+// that other long numbers such as timestamps and IDs are usually kept.
+var cardNumberExpression = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// DefaultRedactPolicy returns a redaction policy for common sensitive parameters, such as passwords and tokens, and
+// bearer tokens and payment card numbers in messages.
+func DefaultRedactPolicy() RedactPolicy {
+	return RedactPolicy{
+		Keys: []string{
+			"password",
+			"passwd",
+			"*secret*",
+			"*token*",
+			"authorization",
+			"cookie",
+			"set-cookie",
+			"api_key",
+			"apikey",
+			"api-key",
+			"private_key",
+		},
+		MessageExpressions: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+			cardNumberExpression,
+		},
+	}
+}
+
+// Secret is a string that is always redacted when it is logged or formatted, regardless of the redaction policy. Use
+// string(secret) to get the actual value.
+type Secret string
+
+// String returns "[REDACTED]"
+func (Secret) String() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter so that the secret is redacted for all verbs
+func (Secret) Format(f fmt.State, verb rune) {
+	f.Write([]byte(redacted))
+}
+
+// MarshalText returns "[REDACTED]"
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// redactKey returns true if the value of the parameter with the given key should be redacted. Keys containing dots,
+// such as the fields of an expanded error like "error.password", are also matched using the part after the last dot.
+func (p RedactPolicy) redactKey(key string) bool {
+	if p.matchKey(key) {
+		return true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return p.matchKey(key[i+1:])
+	}
+	return false
+}
+
+func (p RedactPolicy) matchKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, pattern := range p.Keys {
+		if matchLevelPattern(strings.ToLower(pattern), lowerKey) {
+			return true
+		}
+	}
+	for _, expression := range p.KeyExpressions {
+		if expression.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue returns the replacement for a redacted value
+func (p RedactPolicy) redactValue(v any) string {
+	if !p.Hash {
+		return redacted
+	}
+	value, _ := parameterString(v)
+	hash := sha256.Sum256([]byte(value))
+	return "[sha256:" + hex.EncodeToString(hash[:6]) + "]"
+}
+
+// redactParameters returns the parameters with any sensitive values redacted. The given parameters are never modified.
+func (s *Source) redactParameters(parameters map[string]any) map[string]any {
+	if s == nil || s.instance == nil || len(parameters) == 0 {
+		return parameters
+	}
+	policy := s.instance.Redaction
+	if len(policy.Keys) == 0 && len(policy.KeyExpressions) == 0 && len(policy.MessageExpressions) == 0 {
+		return parameters
+	}
+
+	var out map[string]any
+	set := func(k string, v any) {
+		if out == nil {
+			out = make(map[string]any, len(parameters))
+			for k, v := range parameters {
+				out[k] = v
+			}
+		}
+		out[k] = v
+	}
+	for k, v := range parameters {
+		if policy.redactKey(k) {
+			set(k, policy.redactValue(v))
+			continue
+		}
+		if len(policy.MessageExpressions) == 0 {
+			continue
+		}
+		// String values, and errors and other values formatted as strings, are redacted the same as the message so that
+		// the parameters and message agree. Values that are redacted are replaced with the redacted string.
+		var value string
+		switch t := v.(type) {
+		case string:
+			value = t
+		case error, fmt.Stringer:
+			value = fmt.Sprintf("%v", t)
+		default:
+			continue
+		}
+		if r := policy.redactMessage(value); r != value {
+			set(k, r)
+		}
+	}
+	if out == nil {
+		return parameters
+	}
+	return out
+}
+
+// redactMessage returns the message with any matches of the message expressions redacted
+func (p RedactPolicy) redactMessage(message string) string {
+	for _, expression := range p.MessageExpressions {
+		if expression == cardNumberExpression {
+			message = expression.ReplaceAllStringFunc(message, func(match string) string {
+				if luhnValid(match) {
+					return redacted
+				}
+				return match
+			})
+			continue
+		}
+		if expression.NumSubexp() == 0 {
+			message = expression.ReplaceAllLiteralString(message, redacted)
+			continue
+		}
+
+		b := &strings.Builder{}
+		last := 0
+		for _, match := range expression.FindAllStringSubmatchIndex(message, -1) {
+			start, end := match[2], match[3]
+			if start < 0 {
+				continue
+			}
+			b.WriteString(message[last:start])
+			b.WriteString(redacted)
+			last = end
+		}
+		b.WriteString(message[last:])
+		message = b.String()
+	}
+	return message
+}
+
+// luhnValid returns true if the digits in number pass the Luhn check used by payment card numbers
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
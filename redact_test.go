@@ -0,0 +1,191 @@
+package logtic_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestRedaction(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Redaction = logtic.DefaultRedactPolicy()
+	logtic.Log.Redaction.KeyExpressions = []*regexp.Regexp{regexp.MustCompile(`^ssn$`)}
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	source := logtic.Log.Connect("test")
+
+	parameters := map[string]any{
+		"username":     "alice",
+		"Password":     "hunter2",
+		"access_token": "abc123",
+		"ssn":          "123-45-6789",
+	}
+	source.PInfo("Login", parameters)
+	source.With(map[string]any{"Authorization": "Basic abc"}).Info("request")
+	source.Info("sent header Authorization: Bearer eyJhbGciOi.eyJzdWIi and card 4111 1111 1111 1111")
+
+	if len(sink.events) != 3 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	expected := []string{
+		"Login: Password='[REDACTED]' access_token='[REDACTED]' ssn='[REDACTED]' username='alice'",
+		"request: Authorization='[REDACTED]'",
+		"sent header Authorization: Bearer [REDACTED] and card [REDACTED]",
+	}
+	for i, message := range expected {
+		if sink.events[i].Message != message {
+			t.Errorf("Unexpected message.\nExpected: %s\nGot:      %s", message, sink.events[i].Message)
+		}
+	}
+	if sink.events[0].Parameters["Password"] != "[REDACTED]" {
+		t.Errorf("Parameter not redacted: %v", sink.events[0].Parameters)
+	}
+	if parameters["Password"] != "hunter2" {
+		t.Errorf("Original parameters were modified")
+	}
+
+	logtic.Log.Redaction.Hash = true
+	sink.events = nil
+	source.PInfo("Login", map[string]any{"password": "hunter2"})
+	source.PInfo("Login", map[string]any{"password": "hunter2"})
+	hashed := regexp.MustCompile(`^Login: password='\[sha256:[0-9a-f]{12}\]'$`)
+	if !hashed.MatchString(sink.events[0].Message) || sink.events[0].Message != sink.events[1].Message {
+		t.Errorf("Unexpected hashed messages: '%s' '%s'", sink.events[0].Message, sink.events[1].Message)
+	}
+	if strings.Contains(sink.events[0].Message, "hunter2") {
+		t.Errorf("Secret value in hashed message")
+	}
+}
+
+func TestRedactionParameterValues(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Redaction = logtic.DefaultRedactPolicy()
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	logtic.Log.Connect("test").PInfo("payment", map[string]any{
+		"card":  "4111 1111 1111 1111",
+		"note":  "Bearer abc.def",
+		"error": errors.New("auth failed: Bearer abc.def"),
+	})
+
+	line := string((&logtic.JSONFormatter{}).Format(sink.events[0]))
+	for _, expected := range []string{
+		`"card":"[REDACTED]"`,
+		`"error":"auth failed: Bearer [REDACTED]"`,
+		`"note":"Bearer [REDACTED]"`,
+	} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("Parameters not redacted in JSON output.\nExpected: %s\nGot:      %s", expected, line)
+		}
+	}
+	if strings.Contains(line, "4111") || strings.Contains(line, "abc.def") {
+		t.Errorf("Sensitive value in JSON output: %s", line)
+	}
+}
+
+type credentialsError struct{}
+
+func (credentialsError) Error() string {
+	return "invalid credentials"
+}
+
+func (credentialsError) ErrorFields() map[string]any {
+	return map[string]any{"username": "alice", "password": "hunter2"}
+}
+
+func TestRedactionErrorFields(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Redaction = logtic.DefaultRedactPolicy()
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	logtic.Log.Connect("test").ErrorE(credentialsError{}, "Login failed", nil)
+
+	parameters := sink.events[0].Parameters
+	if parameters["error.password"] != "[REDACTED]" || parameters["error.username"] != "alice" {
+		t.Errorf("Unexpected parameters: %v", parameters)
+	}
+	if strings.Contains(sink.events[0].Message, "hunter2") {
+		t.Errorf("Sensitive value in message: %s", sink.events[0].Message)
+	}
+}
+
+func TestRedactionCardNumbers(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Redaction = logtic.DefaultRedactPolicy()
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	source := logtic.Log.Connect("test")
+	source.Info("card 5500-0055-5555-5559 ms=1712345678901")
+	source.PInfo("order", map[string]any{"order_id": int64(9223372036854775807)})
+
+	expected := []string{
+		"card [REDACTED] ms=1712345678901",
+		"order: order_id=9223372036854775807",
+	}
+	for i, message := range expected {
+		if sink.events[i].Message != message {
+			t.Errorf("Unexpected message.\nExpected: %s\nGot:      %s", message, sink.events[i].Message)
+		}
+	}
+}
+
+func TestSecret(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+	source := logtic.Log.Connect("test")
+
+	secret := logtic.Secret("hunter2")
+	source.PInfo("Login", map[string]any{"key": secret})
+	source.Info("key is %s %v %q %#v", secret, secret, secret, secret)
+
+	if sink.events[0].Message != "Login: key='[REDACTED]'" {
+		t.Errorf("Unexpected message '%s'", sink.events[0].Message)
+	}
+	if sink.events[1].Message != "key is [REDACTED] [REDACTED] [REDACTED] [REDACTED]" {
+		t.Errorf("Unexpected message '%s'", sink.events[1].Message)
+	}
+
+	data, _ := json.Marshal(map[string]any{"key": secret})
+	if string(data) != `{"key":"[REDACTED]"}` {
+		t.Errorf("Unexpected JSON '%s'", data)
+	}
+	if fmt.Sprint(secret) != "[REDACTED]" || string(secret) != "hunter2" {
+		t.Errorf("Unexpected secret value")
+	}
+}
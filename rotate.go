@@ -3,9 +3,31 @@ package logtic
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"time"
 )
 
+// RotatePolicy describes when the log file of a logging instance is automatically rotated and how many rotated log
+// files are kept. Automatic rotation happens when an event is written and uses the same naming as RotateDate.
+type RotatePolicy struct {
+	// MaxSize is the maximum size in bytes of the log file. The log file is rotated before an event would cause it to
+	// exceed this size. Zero disables size-based rotation.
+	MaxSize int64
+	// MaxAge is the maximum age of the log file before it is rotated. The age of an existing log file is measured from
+	// the time of its first event or, if that cannot be read, the time it was last modified. Zero disables age-based
+	// rotation.
+	MaxAge time.Duration
+	// MaxFiles is the maximum number of rotated log files to keep. The oldest rotated log files are removed after each
+	// rotation. Zero keeps all rotated log files.
+	MaxFiles int
+	// Compressor is used to compress rotated log files in the background, adding its extension to the name of the
+	// rotated file. Nil disables compression.
+	Compressor Compressor
+}
+
 // Rotate allows you to rate the log file of this logging instance.
 //
 // When Rotate is called, all pending operations are completed and the current log file is closed.
@@ -14,70 +36,127 @@ import (
 // that you either panic or call logger.Reset() as logtic may be in an undefined state and log calls
 // may cause panics.
 //
-// If `act` returns nil, then the `Open()` method is called for this logger and if successful
-// we return logging operations.
+// If `act` returns nil, then the log file is opened again using the file path of this logger and if successful
+// we resume logging operations.
 //
 // If no log file has been opened on this logger, calls to Rotate do nothing.
 func (l *Logger) Rotate(act func() error) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
 	if l.file == nil {
 		return nil
 	}
+	return l.rotate(act)
+}
 
-	l.lock.Lock()
-	defer l.lock.Unlock()
+func (l *Logger) rotate(act func() error) error {
+	if err := l.closeFile(); err != nil {
+		return err
+	}
 
 	if err := act(); err != nil {
 		fmt.Fprintf(os.Stderr, "Log rotation failed: %s", err.Error())
 		return err
 	}
 
-	if err := Log.Open(); err != nil {
+	if err := l.openFile(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error opening new log file '%s': %s", l.FilePath, err.Error())
 		return err
 	}
 
+	if l.rotatedPath != "" && l.Rotation.Compressor != nil {
+		l.compressRotatedFile(l.rotatedPath, l.Rotation.Compressor)
+	}
+	l.rotatedPath = ""
+
+	if l.Rotation.MaxFiles > 0 {
+		l.pruneRotatedFiles()
+	}
+
 	return nil
 }
 
 // RotateDate will rotate the log file of this logging instance. The current log file will be renamed and suffixed
 // with the current date in a YYYY-MM-DD format. A new log file will be opened with the original file path and used for
 // all subsequent writes. Writes will be blocked while the rotation is in progress. If a file matching the name of what
-// would be used for the rotated file, a dash and numerical suffix is added to the end of the name.
+// would be used for the rotated file, a dash and numerical suffix is added to the end of the name. If the rotation
+// policy has a compressor, the rotated file is compressed in the background.
 //
 // If an error is returned during rotation it is highly recommended that you either panic or call logger.Reset()
 // as logtic may be in an undefined state and log calls may cause panics.
 //
 // If no log file has been opened on this logger, calls to RotateDate do nothing.
 func (l *Logger) RotateDate() error {
-	return l.Rotate(func() error {
-		date := time.Now().Format("2006-01-02")
-		newPath := l.FilePath + "." + date
-
-		if fileExists(newPath) {
-			i := 1
-			for fileExists(fmt.Sprintf("%s-%d", newPath, i)) {
-				i++
-			}
-			newPath = fmt.Sprintf("%s-%d", newPath, i)
-		}
+	return l.Rotate(l.rotateFileDate)
+}
 
-		if err := l.file.Sync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error syncing changes to existing log file: %s", err.Error())
-			return err
-		}
-		if err := l.file.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error closing existing log file: %s", err.Error())
-			return err
-		}
-		l.file = nil
+func (l *Logger) rotateFileDate() error {
+	date := time.Now().Format("2006-01-02")
+	newPath := l.FilePath + "." + date
 
-		if err := os.Rename(l.FilePath, newPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error renaming existing log file: %s", err.Error())
-			return err
+	extension := ""
+	if l.Rotation.Compressor != nil {
+		extension = l.Rotation.Compressor.Extension()
+	}
+	exists := func(p string) bool {
+		return fileExists(p) || (extension != "" && fileExists(p+extension))
+	}
+
+	if exists(newPath) {
+		i := 1
+		for exists(fmt.Sprintf("%s-%d", newPath, i)) {
+			i++
 		}
+		newPath = fmt.Sprintf("%s-%d", newPath, i)
+	}
+
+	if err := os.Rename(l.FilePath, newPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error renaming existing log file: %s", err.Error())
+		return err
+	}
+	l.rotatedPath = newPath
+
+	return nil
+}
+
+// Reopen will close and reopen the log file of this logging instance. Use Reopen after the log file has been moved by
+// an external tool, such as logrotate, so that subsequent writes go to a new file at the original file path. Writes
+// will be blocked while the file is reopened.
+//
+// If no log file has been opened on this logger, calls to Reopen do nothing.
+func (l *Logger) Reopen() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
 
+	if l.file == nil {
 		return nil
-	})
+	}
+	if err := l.closeFile(); err != nil {
+		return err
+	}
+	if err := l.openFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening new log file '%s': %s", l.FilePath, err.Error())
+		return err
+	}
+	return nil
+}
+
+// closeFile will sync and close the current log file. The lock must already be held.
+func (l *Logger) closeFile() error {
+	if l.file == nil {
+		return nil
+	}
+	if err := l.file.Sync(); err != nil && l.fileRegular {
+		fmt.Fprintf(os.Stderr, "Error syncing changes to existing log file: %s", err.Error())
+		return err
+	}
+	if err := l.file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error closing existing log file: %s", err.Error())
+		return err
+	}
+	l.file = nil
+	return nil
 }
 
 func fileExists(filePath string) bool {
@@ -86,3 +165,99 @@ func fileExists(filePath string) bool {
 	}
 	return true
 }
+
+// fileStartTimePattern matches the time at the start of a line written by the TextFormatter or JSONFormatter
+var fileStartTimePattern = regexp.MustCompile(`^(?:\{"time":")?([0-9]{4}-[0-9]{2}-[0-9]{2}T[^ "]+)`)
+
+// fileStartTime returns the time of the first event in an existing log file, so that the age of the file is kept when
+// it is reopened. If the time cannot be found, the modification time of the file is used.
+func fileStartTime(f *os.File, info os.FileInfo) time.Time {
+	buf := make([]byte, 128)
+	n, _ := f.ReadAt(buf, 0)
+	if match := fileStartTimePattern.FindSubmatch(buf[:n]); match != nil {
+		if t, err := time.Parse(time.RFC3339, string(match[1])); err == nil {
+			return t
+		}
+	}
+	return info.ModTime()
+}
+
+// rotateIfNeeded will rotate the log file if writing n more bytes would violate the rotation policy. The lock must
+// already be held.
+func (l *Logger) rotateIfNeeded(n int) {
+	if !l.fileRegular || l.fileSize == 0 {
+		return
+	}
+
+	policy := l.Rotation
+	sizeExceeded := policy.MaxSize > 0 && l.fileSize+int64(n) > policy.MaxSize
+	ageExceeded := policy.MaxAge > 0 && time.Since(l.fileTime) >= policy.MaxAge
+	if !sizeExceeded && !ageExceeded {
+		return
+	}
+
+	l.rotate(l.rotateFileDate)
+}
+
+// pruneRotatedFiles will remove the oldest rotated log files, keeping at most MaxFiles
+func (l *Logger) pruneRotatedFiles() {
+	dir, base := filepath.Split(l.FilePath)
+	if dir == "" {
+		dir = "."
+	}
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(base) + `\.([0-9]{4}-[0-9]{2}-[0-9]{2})(?:-([0-9]+))?(?:\.[A-Za-z0-9]+)?$`)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing rotated log files: %s", err.Error())
+		return
+	}
+
+	// A rotated file may exist both uncompressed and compressed while it is being compressed
+	type rotatedFile struct {
+		paths []string
+		date  string
+		index int
+	}
+	files := []*rotatedFile{}
+	fileMap := map[string]*rotatedFile{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		key := match[1] + "-" + match[2]
+		file, ok := fileMap[key]
+		if !ok {
+			index := 0
+			if match[2] != "" {
+				index, _ = strconv.Atoi(match[2])
+			}
+			file = &rotatedFile{date: match[1], index: index}
+			fileMap[key] = file
+			files = append(files, file)
+		}
+		file.paths = append(file.paths, filepath.Join(dir, entry.Name()))
+	}
+	if len(files) <= l.Rotation.MaxFiles {
+		return
+	}
+
+	// Newest first, rotated files are named by date and then by a counter for the same date
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].date != files[j].date {
+			return files[i].date > files[j].date
+		}
+		return files[i].index > files[j].index
+	})
+	for _, file := range files[l.Rotation.MaxFiles:] {
+		for _, p := range file.paths {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Error removing rotated log file '%s': %s", p, err.Error())
+			}
+		}
+	}
+}
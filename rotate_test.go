@@ -3,6 +3,7 @@ package logtic_test
 import (
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -117,3 +118,167 @@ func TestRotateDuplicate(t *testing.T) {
 	}
 	fileIsGreaterThan1Byte(currentPath, t)
 }
+
+func TestRotateSize(t *testing.T) {
+	Setup()
+
+	dir := t.TempDir()
+
+	logtic.Log.FilePath = path.Join(dir, "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Rotation = logtic.RotatePolicy{
+		MaxSize:  256,
+		MaxFiles: 3,
+	}
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	s := logtic.Log.Connect("Test")
+	i := 0
+	for i < 100 {
+		i++
+		s.Debug("Count %d", i)
+	}
+	logtic.Log.Close()
+
+	date := time.Now().Format("2006-01-02")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Error listing log directory: %s", err.Error())
+	}
+	if len(entries) != 4 {
+		t.Errorf("Unexpected number of log files. Expected 4 got %d", len(entries))
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("Error stating log file: %s", err.Error())
+		}
+		if info.Size() > 256 {
+			t.Errorf("Log file '%s' exceeds maximum size: %d", entry.Name(), info.Size())
+		}
+		if entry.Name() == "app.log."+date {
+			t.Errorf("Oldest rotated log file was not removed")
+		}
+	}
+
+	data, err := os.ReadFile(path.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if !strings.Contains(string(data), "Count 100") {
+		t.Errorf("Current log file does not contain latest event")
+	}
+}
+
+func TestRotateAge(t *testing.T) {
+	Setup()
+
+	dir := t.TempDir()
+
+	logtic.Log.FilePath = path.Join(dir, "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Rotation = logtic.RotatePolicy{
+		MaxAge: time.Millisecond,
+	}
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	s := logtic.Log.Connect("Test")
+	s.Debug("Before rotation")
+	time.Sleep(5 * time.Millisecond)
+	s.Debug("After rotation")
+	logtic.Log.Close()
+
+	date := time.Now().Format("2006-01-02")
+	fileIsGreaterThan1Byte(path.Join(dir, "app.log."+date), t)
+	fileIsGreaterThan1Byte(path.Join(dir, "app.log"), t)
+}
+
+func TestRotateAgeExistingFile(t *testing.T) {
+	Setup()
+
+	dir := t.TempDir()
+
+	// The age of an existing file is based on its first event, not when it was opened
+	existing := time.Now().Add(-2*time.Hour).Format(time.RFC3339) + " [INFO][Test] Before restart\n"
+	if err := os.WriteFile(path.Join(dir, "app.log"), []byte(existing), 0644); err != nil {
+		t.Fatalf("Error writing log file: %s", err.Error())
+	}
+
+	logtic.Log.FilePath = path.Join(dir, "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Rotation = logtic.RotatePolicy{
+		MaxAge: time.Hour,
+	}
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	logtic.Log.Connect("Test").Debug("After restart")
+	logtic.Log.Close()
+
+	date := time.Now().Format("2006-01-02")
+	data, err := os.ReadFile(path.Join(dir, "app.log."+date))
+	if err != nil {
+		t.Fatalf("Existing log file was not rotated: %s", err.Error())
+	}
+	if string(data) != existing {
+		t.Errorf("Unexpected rotated log file: %s", data)
+	}
+}
+
+func TestReopenConcurrentClose(t *testing.T) {
+	Setup()
+
+	logtic.Log.FilePath = path.Join(t.TempDir(), "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			logtic.Log.Reopen()
+		}
+	}()
+	s := logtic.Log.Connect("Test")
+	for i := 0; i < 100; i++ {
+		s.Debug("Count %d", i)
+	}
+	logtic.Log.Close()
+	<-done
+	logtic.Log.Close()
+}
+
+func TestRotateConcurrentReopen(t *testing.T) {
+	Setup()
+
+	logtic.Log.FilePath = path.Join(t.TempDir(), "app.log")
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			logtic.Log.Reopen()
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		if err := logtic.Log.Rotate(func() error { return nil }); err != nil {
+			t.Errorf("Error rotating log file: %s", err.Error())
+		}
+	}
+	<-done
+}
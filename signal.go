@@ -0,0 +1,64 @@
+package logtic
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// HandleSignals will start handling signals for this logging instance, returning a function to stop handling signals.
+// Signals are only supported on Unix-like platforms, on other platforms HandleSignals does nothing. The signals are:
+//
+//	SIGHUP   reopen the log file, see Logger.Reopen
+//	SIGUSR1  change to the next more verbose level, cycling back to the original level after LevelTrace
+//	SIGUSR2  restore the original level
+//
+// The original level is the level of this instance when HandleSignals was called.
+func (l *Logger) HandleSignals() (stop func()) {
+	if !signalsSupported {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, reopenSignal, cycleLevelSignal, resetLevelSignal)
+
+	l.levelLock.RLock()
+	originalLevel := l.Level
+	l.levelLock.RUnlock()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				switch sig {
+				case reopenSignal:
+					l.Reopen()
+				case cycleLevelSignal:
+					l.levelLock.RLock()
+					level, ok := l.Level.moreVerbose()
+					l.levelLock.RUnlock()
+					if !ok {
+						level = originalLevel
+					}
+					l.setLevelFor(level, 0)
+				case resetLevelSignal:
+					l.setLevelFor(originalLevel, 0)
+				}
+			}
+		}
+	}()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			<-stopped
+		})
+	}
+}
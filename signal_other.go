@@ -0,0 +1,13 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+
+package logtic
+
+import "os"
+
+const signalsSupported = false
+
+var (
+	reopenSignal     os.Signal
+	cycleLevelSignal os.Signal
+	resetLevelSignal os.Signal
+)
@@ -0,0 +1,78 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package logtic_test
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestHandleSignals(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	defer logtic.Log.Close()
+
+	stop := logtic.Log.HandleSignals()
+	defer stop()
+
+	source := logtic.Log.Connect("test")
+	source.Info("before rotation")
+	if err := os.Rename(logPath, logPath+".1"); err != nil {
+		t.Fatalf("Error renaming log file: %s", err.Error())
+	}
+
+	waitFor := func(description string, check func() bool) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !check() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %s", description)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	waitFor("log file to be reopened", func() bool {
+		_, err := os.Stat(logPath)
+		return err == nil
+	})
+	source.Info("after rotation")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if strings.Contains(string(data), "before rotation") || !strings.Contains(string(data), "after rotation") {
+		t.Errorf("Unexpected log file after reopen: '%s'", data)
+	}
+
+	handler := logtic.Log.LevelHandler()
+	level := func() string {
+		_, state := levelRequest(t, handler, http.MethodGet, "/", "")
+		return state.Level
+	}
+
+	for _, expected := range []string{"DEBUG", "TRACE", "INFO", "DEBUG"} {
+		syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		waitFor("level to change to "+expected, func() bool { return level() == expected })
+	}
+
+	syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+	waitFor("level to be restored", func() bool { return level() == "INFO" })
+
+	stop()
+	stop()
+}
@@ -0,0 +1,13 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package logtic
+
+import "syscall"
+
+const signalsSupported = true
+
+var (
+	reopenSignal     = syscall.SIGHUP
+	cycleLevelSignal = syscall.SIGUSR1
+	resetLevelSignal = syscall.SIGUSR2
+)
@@ -0,0 +1,141 @@
+package logtic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Event describes a single log event that is passed to sinks and formatters
+type Event struct {
+	// The time the event was created
+	Time time.Time
+	// The level of the event
+	Level LogLevel
+	// The name of the source that created the event
+	Source string
+	// The formatted message of the event. For parameterized events this includes the event name and the rendered
+	// parameters.
+	Message string
+	// The event name of a parameterized event. Empty for formatted events.
+	Event string
+	// The parameters of a parameterized event. Nil for formatted events.
+	Parameters map[string]any
+	// The location in code where the event was written. Nil unless LoggerOptions.Caller is enabled.
+	Caller *Caller
+	// The formatted stack of the goroutine that wrote the event, or where a recovered panic happened. Empty unless
+	// LoggerOptions.Stack is enabled or the event was written by Source.Recover.
+	Stack string
+	// The trace context of the event. Nil unless the event was written with a context that has a trace context, see
+	// TraceContextFromContext.
+	Trace *TraceContext
+}
+
+// Sink describes an interface for a destination of log events. Sinks are attached to a logging instance using
+// Logger.AddSink and receive every event at or above their minimum level, in addition to the log file and console.
+//
+// Sinks are never called concurrently by the same logging instance. Sinks that also implement io.Closer are closed
+// when the logging instance is closed, and sinks that implement Flusher are flushed when the logging instance is
+// flushed.
+type Sink interface {
+	// WriteEvent is called for each event that is captured by the logging instance.
+	WriteEvent(event Event) error
+}
+
+// Flusher describes an optional interface for sinks that write events in the background, such as the network sinks.
+// Flush is called by Logger.Flush, including before the process exits from Fatal or Panic.
+type Flusher interface {
+	// Flush waits for all pending events to be written
+	Flush()
+}
+
+type tSinkEntry struct {
+	sink  Sink
+	level LogLevel
+}
+
+// AddSink will attach the given sink to this logging instance. Events must satisfy the level of the source or
+// instance, then any event at or above the given level is passed to the sink.
+func (l *Logger) AddSink(sink Sink, level LogLevel) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.sinks = append(l.sinks, tSinkEntry{sink: sink, level: level})
+}
+
+// RemoveSink will detach the given sink from this logging instance. The sink is not closed.
+func (l *Logger) RemoveSink(sink Sink) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	sinks := make([]tSinkEntry, 0, len(l.sinks))
+	for _, entry := range l.sinks {
+		if entry.sink != sink {
+			sinks = append(sinks, entry)
+		}
+	}
+	l.sinks = sinks
+}
+
+// flushSinks flushes any attached sinks that implement Flusher. The sinks are flushed without holding the lock so that
+// events can still be written while waiting.
+func (l *Logger) flushSinks() {
+	l.lock.Lock()
+	flushers := []Flusher{}
+	for _, entry := range l.sinks {
+		if flusher, ok := entry.sink.(Flusher); ok {
+			flushers = append(flushers, flusher)
+		}
+	}
+	l.lock.Unlock()
+
+	for _, flusher := range flushers {
+		flusher.Flush()
+	}
+}
+
+func (l *Logger) writeSinks(event Event) {
+	for _, entry := range l.sinks {
+		if !entry.level.allows(event.Level) {
+			continue
+		}
+		if err := entry.sink.WriteEvent(event); err != nil {
+			fmt.Fprintf(os.Stderr, "logtic: error writing event to sink: %s\n", err.Error())
+		}
+	}
+}
+
+func (l *Logger) closeSinks() {
+	for _, entry := range l.sinks {
+		if closer, ok := entry.sink.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	l.sinks = nil
+}
+
+// WriterSink is a sink that writes formatted events to any writer, with each event on its own line.
+type WriterSink struct {
+	// Writer is where formatted events are written to
+	Writer io.Writer
+	// Formatter is used to format each event. Defaults to a TextFormatter.
+	Formatter Formatter
+}
+
+// NewWriterSink will create a new sink that writes events formatted by formatter to w. If formatter is nil, a
+// TextFormatter is used.
+func NewWriterSink(w io.Writer, formatter Formatter) *WriterSink {
+	return &WriterSink{
+		Writer:    w,
+		Formatter: formatter,
+	}
+}
+
+// WriteEvent writes the formatted event to the writer
+func (w *WriterSink) WriteEvent(event Event) error {
+	formatter := w.Formatter
+	if formatter == nil {
+		formatter = &TextFormatter{}
+	}
+	_, err := w.Writer.Write(append(formatter.Format(event), '\n'))
+	return err
+}
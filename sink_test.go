@@ -0,0 +1,127 @@
+package logtic_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type testSink struct {
+	events []logtic.Event
+	closed bool
+}
+
+func (s *testSink) WriteEvent(event logtic.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	debugSink := &testSink{}
+	warnSink := &testSink{}
+	logtic.Log.AddSink(debugSink, logtic.LevelDebug)
+	logtic.Log.AddSink(warnSink, logtic.LevelWarn)
+
+	source := logtic.Log.Connect("test")
+	source.Debug("debug message")
+	source.Info("info message")
+	source.PWarn("warn event", map[string]any{"key": "value"})
+	source.Error("error message")
+
+	if len(debugSink.events) != 4 {
+		t.Errorf("Unexpected number of events for debug sink. Expected 4 got %d", len(debugSink.events))
+	}
+	if len(warnSink.events) != 2 {
+		t.Fatalf("Unexpected number of events for warn sink. Expected 2 got %d", len(warnSink.events))
+	}
+
+	event := warnSink.events[0]
+	if event.Level != logtic.LevelWarn {
+		t.Errorf("Unexpected event level. Expected %d got %d", logtic.LevelWarn, event.Level)
+	}
+	if event.Source != "test" {
+		t.Errorf("Unexpected event source. Expected 'test' got '%s'", event.Source)
+	}
+	if event.Event != "warn event" {
+		t.Errorf("Unexpected event name. Expected 'warn event' got '%s'", event.Event)
+	}
+	if event.Message != "warn event: key='value'" {
+		t.Errorf("Unexpected event message. Expected \"warn event: key='value'\" got \"%s\"", event.Message)
+	}
+	if event.Parameters["key"] != "value" {
+		t.Errorf("Unexpected event parameters: %v", event.Parameters)
+	}
+	if event.Time.IsZero() {
+		t.Errorf("Event time not set")
+	}
+
+	logtic.Log.RemoveSink(debugSink)
+	source.Error("error message")
+	if len(debugSink.events) != 4 {
+		t.Errorf("Event written to removed sink")
+	}
+
+	logtic.Log.Close()
+	if debugSink.closed {
+		t.Errorf("Removed sink was closed")
+	}
+	if !warnSink.closed {
+		t.Errorf("Sink was not closed")
+	}
+}
+
+func TestSinkInstanceLevel(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelWarn
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.Debug("debug message")
+	source.Warn("warn message")
+
+	if len(sink.events) != 1 {
+		t.Errorf("Unexpected number of events for sink. Expected 1 got %d", len(sink.events))
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	b := &bytes.Buffer{}
+	logtic.Log.AddSink(logtic.NewWriterSink(b, nil), logtic.LevelInfo)
+
+	source := logtic.Log.Connect("test")
+	source.Debug("this is a %s message", "debug")
+	source.Info("this is an %s message", "info")
+	logtic.Log.Close()
+
+	pattern := regexp.MustCompile(`^[0-9\-:TZ+]+ \[INFO\]\[test\] this is an info message\n$`)
+	if !pattern.Match(b.Bytes()) {
+		t.Errorf("Unexpected output from writer sink: '%s'", b.String())
+	}
+}
@@ -0,0 +1,129 @@
+//go:build go1.21
+
+package logtic
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler returns a handler that acts as a proxy between the log/slog package and logtic. Records sent to this
+// handler are written to this source. Attributes are written as parameters, with attributes inside of groups having
+// their key prefixed by the group name, such as "group.key". Parameters carried by the context of a record are
+// included, see NewContext.
+//
+// For example, to send all events from the default slog logger to logtic:
+//
+//	slog.SetDefault(slog.New(source.SlogHandler()))
+func (s *Source) SlogHandler() slog.Handler {
+	return &tSlogHandler{
+		source: s,
+	}
+}
+
+type tSlogHandler struct {
+	source     *Source
+	parameters map[string]any
+	prefix     string
+}
+
+// levelFromSlog returns the logtic level for the given slog level. Levels below slog.LevelDebug are trace, levels
+// between slog.LevelInfo and slog.LevelWarn are notice (starting at slog.LevelInfo+2), and levels above slog.LevelError
+// are critical (starting at slog.LevelError+4).
+func levelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelInfo+2:
+		return LevelInfo
+	case level < slog.LevelWarn:
+		return LevelNotice
+	case level < slog.LevelError:
+		return LevelWarn
+	case level < slog.LevelError+4:
+		return LevelError
+	default:
+		return LevelCritical
+	}
+}
+
+func (h *tSlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	s := h.source
+	if s == nil || s.instance == nil || !s.instance.opened {
+		return false
+	}
+	return !s.checkLevel(levelFromSlog(level))
+}
+
+func (h *tSlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	level := levelFromSlog(record.Level)
+
+	parameters := make(map[string]any, len(h.parameters)+record.NumAttrs())
+	for k, v := range h.parameters {
+		parameters[k] = v
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(parameters, h.prefix, attr)
+		return true
+	})
+
+	if len(parameters) == 0 {
+		h.source.logCtx(ctx, level, "%s", record.Message)
+	} else {
+		h.source.plogCtx(ctx, level, record.Message, parameters)
+	}
+	return nil
+}
+
+func (h *tSlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	parameters := make(map[string]any, len(h.parameters)+len(attrs))
+	for k, v := range h.parameters {
+		parameters[k] = v
+	}
+	for _, attr := range attrs {
+		addSlogAttr(parameters, h.prefix, attr)
+	}
+
+	return &tSlogHandler{
+		source:     h.source,
+		parameters: parameters,
+		prefix:     h.prefix,
+	}
+}
+
+func (h *tSlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &tSlogHandler{
+		source:     h.source,
+		parameters: h.parameters,
+		prefix:     h.prefix + name + ".",
+	}
+}
+
+func addSlogAttr(parameters map[string]any, prefix string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix = prefix + attr.Key + "."
+		}
+		for _, groupAttr := range value.Group() {
+			addSlogAttr(parameters, groupPrefix, groupAttr)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+
+	parameters[prefix+attr.Key] = value.Any()
+}
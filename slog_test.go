@@ -0,0 +1,105 @@
+//go:build go1.21
+
+package logtic_test
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestSlogHandler(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	logger := slog.New(logtic.Log.Connect("slog").SlogHandler())
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.With("request", 123).WithGroup("http").Warn("warn message", "status", 404, slog.Group("client", "ip", "127.0.0.1"))
+	logger.Log(context.Background(), slog.LevelError+4, "critical message")
+
+	if len(sink.events) != 3 {
+		t.Fatalf("Unexpected number of events. Expected 3 got %d", len(sink.events))
+	}
+
+	info := sink.events[0]
+	if info.Level != logtic.LevelInfo || info.Message != "info message" || info.Parameters != nil {
+		t.Errorf("Unexpected info event: %+v", info)
+	}
+
+	warn := sink.events[1]
+	if warn.Level != logtic.LevelWarn || warn.Source != "slog" || warn.Event != "warn message" {
+		t.Errorf("Unexpected warn event: %+v", warn)
+	}
+	expected := map[string]any{
+		"request":        int64(123),
+		"http.status":    int64(404),
+		"http.client.ip": "127.0.0.1",
+	}
+	if len(warn.Parameters) != len(expected) {
+		t.Errorf("Unexpected parameters: %v", warn.Parameters)
+	}
+	for k, v := range expected {
+		if warn.Parameters[k] != v {
+			t.Errorf("Unexpected value for parameter '%s'. Expected %#v got %#v", k, v, warn.Parameters[k])
+		}
+	}
+
+	if sink.events[2].Level != logtic.LevelCritical {
+		t.Errorf("Unexpected level for critical event: %d", sink.events[2].Level)
+	}
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	logtic.Log.Options.Caller = true
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	logger := slog.New(logtic.Log.Connect("slog").SlogHandler())
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("info message")
+
+	if len(sink.events) != 1 || sink.events[0].Caller == nil {
+		t.Fatalf("Caller not captured")
+	}
+	if caller := sink.events[0].Caller; caller.File != file || caller.Line != line+1 {
+		t.Errorf("Unexpected caller. Expected %s:%d got %s:%d", file, line+1, caller.File, caller.Line)
+	}
+}
+
+func TestSlogHandlerContext(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelInfo
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	logger := slog.New(logtic.Log.Connect("slog").SlogHandler())
+	ctx := logtic.NewContext(context.Background(), map[string]any{"request_id": "abc"})
+	logger.InfoContext(ctx, "info message")
+
+	if len(sink.events) != 1 || sink.events[0].Message != "info message: request_id='abc'" {
+		t.Errorf("Unexpected events: %+v", sink.events)
+	}
+}
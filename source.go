@@ -2,17 +2,17 @@ package logtic
 
 import (
 	"fmt"
-	"io"
 	"os"
-	"runtime/debug"
 	"strings"
+	"time"
 )
 
 // Source describes a source for log events
 type Source struct {
-	Name     string
-	level    *LogLevel
-	instance *Logger
+	Name       string
+	level      *LogLevel
+	instance   *Logger
+	parameters map[string]any
 }
 
 // OverrideLevel will specify a new log level for this source alone, ignoring the log level of the parent instance
@@ -25,98 +25,187 @@ func (s *Source) ClearLevelOverride() {
 	s.level = nil
 }
 
+// With will return a new source with the given parameters bound to it. Bound parameters are included in every event
+// written by the new source, including formatted events. Parameters of a parameterized event take precedence over
+// bound parameters with the same key. Any parameters already bound to this source are also bound to the new source.
+func (s *Source) With(parameters map[string]any) *Source {
+	if s == nil {
+		return nil
+	}
+
+	bound := make(map[string]any, len(s.parameters)+len(parameters))
+	for k, v := range s.parameters {
+		bound[k] = v
+	}
+	for k, v := range parameters {
+		bound[k] = v
+	}
+
+	return &Source{
+		Name:       s.Name,
+		level:      s.level,
+		instance:   s.instance,
+		parameters: bound,
+	}
+}
+
+// Sub will return a new source with a name nested under the name of this source. For example, calling Sub("db") on a
+// source named "App" returns a source named "App/db". Any bound parameters and level override are kept.
+func (s *Source) Sub(name string) *Source {
+	if s == nil {
+		return nil
+	}
+
+	if s.instance != nil {
+		s.instance.addSource(s.Name + "/" + name)
+	}
+	return &Source{
+		Name:       s.Name + "/" + name,
+		level:      s.level,
+		instance:   s.instance,
+		parameters: s.parameters,
+	}
+}
+
+// newEvent returns a formatted event, including any bound parameters
+func (s *Source) newEvent(level LogLevel, format string, a ...interface{}) Event {
+	if s == nil || len(s.parameters) == 0 {
+		return Event{
+			Level:   level,
+			Message: s.formatMessage(format, a...),
+		}
+	}
+
+	parameters := s.redactParameters(s.expandErrors(s.parameters))
+	return Event{
+		Level:      level,
+		Message:    s.formatMessage("%s: %s", fmt.Sprintf(format, a...), StringFromParameters(parameters)),
+		Parameters: parameters,
+	}
+}
+
+// newParameterizedEvent returns a parameterized event, merging any bound parameters with the given parameters. The
+// parameters are always copied, as the event may be written in the background after the caller has reused its map.
+func (s *Source) newParameterizedEvent(level LogLevel, event string, parameters map[string]any) Event {
+	var bound map[string]any
+	if s != nil {
+		bound = s.parameters
+	}
+	merged := make(map[string]any, len(bound)+len(parameters))
+	for k, v := range bound {
+		merged[k] = v
+	}
+	for k, v := range parameters {
+		merged[k] = v
+	}
+	parameters = s.redactParameters(s.expandErrors(merged))
+
+	return Event{
+		Level:      level,
+		Message:    s.formatMessage("%s: %s", event, StringFromParameters(parameters)),
+		Event:      event,
+		Parameters: parameters,
+	}
+}
+
 func (s *Source) formatMessage(format string, a ...interface{}) string {
 	message := fmt.Sprintf(format, a...)
+	if s != nil && s.instance != nil && len(s.instance.Redaction.MessageExpressions) > 0 {
+		message = s.instance.Redaction.redactMessage(message)
+	}
 	if s != nil && s.instance != nil && s.instance.Options.EscapeCharacters {
 		message = escapeCharacters(message)
 	}
 	return message
 }
 
-func (s *Source) write(message string) {
-	s.instance.write(message)
+func (s *Source) write(event Event) {
+	event.Time = time.Now()
+	event.Source = s.Name
+
+	if s.instance == nil {
+		fmt.Fprintf(os.Stderr, "[%s][%s] %s\n", event.Level.String(), s.Name, event.Message)
+		return
+	}
+	if s.instance.Options.Caller && s.instance.Options.CallerLevel.allows(event.Level) {
+		event.Caller = s.caller()
+	}
+	if s.instance.Options.Stack && s.instance.Options.StackLevel.allows(event.Level) && event.Stack == "" {
+		event.Stack = stackTrace()
+	}
+	s.instance.dispatch(event)
 }
 
-func (s *Source) checkLevel(levelWanted LogLevel) bool {
-	if s.level != nil {
-		return *s.level < levelWanted
+func (s *Source) log(level LogLevel, format string, a ...interface{}) {
+	defer s.panicRecover()
+	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(level) {
+		return
 	}
-	return s.instance.Level < levelWanted
+	s.write(s.newEvent(level, format, a...))
 }
 
-func (s *Source) stdout() io.Writer {
-	if s == nil || s.instance == nil {
-		return os.Stdout
+func (s *Source) plog(level LogLevel, event string, parameters map[string]any) {
+	defer s.panicRecover()
+	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(level) {
+		return
 	}
-	return s.instance.Stdout
+	s.write(s.newParameterizedEvent(level, event, parameters))
 }
 
-func (s *Source) stderr() io.Writer {
-	if s == nil || s.instance == nil {
-		return os.Stderr
+func (s *Source) checkLevel(levelWanted LogLevel) bool {
+	if s.level != nil {
+		return !s.level.allows(levelWanted)
 	}
-	return s.instance.Stderr
+	return !s.instance.levelFor(s.Name).allows(levelWanted)
+}
+
+// Trace will log a trace formatted message.
+func (s *Source) Trace(format string, a ...interface{}) {
+	s.log(LevelTrace, format, a...)
 }
 
 // Debug will log a debug formatted message.
 func (s *Source) Debug(format string, a ...interface{}) {
-	defer panicRecover()
-	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(LevelDebug) {
-		return
-	}
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stdout(), "%s %s\n", s.colorHiBlack("[DEBUG]["+s.Name+"]"), message)
-	s.write("[DEBUG][" + s.Name + "] " + message)
+	s.log(LevelDebug, format, a...)
 }
 
 // Info will log an informational formatted message.
 func (s *Source) Info(format string, a ...interface{}) {
-	defer panicRecover()
-	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(LevelInfo) {
-		return
-	}
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stdout(), "%s %s\n", s.colorBlue("[INFO]["+s.Name+"]"), message)
-	s.write("[INFO][" + s.Name + "] " + message)
+	s.log(LevelInfo, format, a...)
+}
+
+// Notice will log a notice formatted message.
+func (s *Source) Notice(format string, a ...interface{}) {
+	s.log(LevelNotice, format, a...)
 }
 
 // Warn will log a warning formatted message.
 func (s *Source) Warn(format string, a ...interface{}) {
-	defer panicRecover()
-	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(LevelWarn) {
-		return
-	}
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stdout(), "%s %s\n", s.colorYellow("[WARN]["+s.Name+"]"), message)
-	s.write("[WARN][" + s.Name + "] " + message)
+	s.log(LevelWarn, format, a...)
 }
 
 // Error will log an error formatted message. Errors are printed to stderr.
 func (s *Source) Error(format string, a ...interface{}) {
-	defer panicRecover()
-	if s == nil || s.instance == nil || !s.instance.opened || s.checkLevel(LevelError) {
-		return
-	}
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stderr(), "%s %s\n", s.colorRed("[ERROR]["+s.Name+"]"), message)
-	s.write("[ERROR][" + s.Name + "] " + message)
+	s.log(LevelError, format, a...)
+}
+
+// Critical will log a critical formatted message. Critical messages are printed to stderr.
+func (s *Source) Critical(format string, a ...interface{}) {
+	s.log(LevelCritical, format, a...)
 }
 
 // Fatal will log a fatal formatted error message and exit the application with status 1.
 // Fatal messages are printed to stderr.
 func (s *Source) Fatal(format string, a ...interface{}) {
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stderr(), "%s %s\n", s.colorRed("[FATAL]["+s.Name+"]"), message)
-	s.write("[FATAL][" + s.Name + "] " + message)
+	s.write(s.newEvent(levelFatal, format, a...))
 	os.Exit(1)
 }
 
 // Panic functions like source.Fatal() but panics rather than exits.
 func (s *Source) Panic(format string, a ...interface{}) {
-	message := s.formatMessage(format, a...)
-	fmt.Fprintf(s.stderr(), "%s %s\n", s.colorRed("[FATAL]["+s.Name+"]"), message)
-	s.write("[FATAL][" + s.Name + "] " + message)
-	panic(message)
+	event := s.newEvent(levelFatal, format, a...)
+	s.write(event)
+	panic(event.Message)
 }
 
 // Write will call the matching write function for the given level, printing the provided message.
@@ -129,14 +218,8 @@ func (s *Source) Panic(format string, a ...interface{}) {
 //	source.Debug("Hello world")
 func (s *Source) Write(level LogLevel, format string, a ...interface{}) {
 	switch level {
-	case LevelDebug:
-		s.Debug(format, a...)
-	case LevelInfo:
-		s.Info(format, a...)
-	case LevelWarn:
-		s.Warn(format, a...)
-	case LevelError:
-		s.Error(format, a...)
+	case LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError, LevelCritical:
+		s.log(level, format, a...)
 	default:
 		return
 	}
@@ -152,10 +235,3 @@ func escapeCharacters(message string) string {
 	message = strings.ReplaceAll(message, "\v", "\\v")
 	return message
 }
-
-func panicRecover() {
-	if r := recover(); r != nil {
-		fmt.Fprintf(os.Stderr, "logtic: recovered from panic writing event. stack to follow.\n")
-		debug.PrintStack()
-	}
-}
@@ -297,3 +297,154 @@ func TestNilLogInstance(t *testing.T) {
 
 	os.Stderr = origStderr
 }
+
+func TestSourceWith(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	request := source.With(map[string]any{"request": 1, "user": "alice"})
+	tenant := request.With(map[string]any{"tenant": "example"})
+
+	request.Info("hello %s", "world")
+	tenant.PWarn("event", map[string]any{"user": "bob"})
+	source.Info("no parameters")
+
+	if len(sink.events) != 3 {
+		t.Fatalf("Unexpected number of events. Expected 3 got %d", len(sink.events))
+	}
+
+	if sink.events[0].Message != "hello world: request=1 user='alice'" {
+		t.Errorf("Unexpected message for formatted event: \"%s\"", sink.events[0].Message)
+	}
+	if sink.events[0].Parameters["request"] != 1 {
+		t.Errorf("Bound parameters not included in formatted event: %v", sink.events[0].Parameters)
+	}
+	if sink.events[1].Message != "event: request=1 tenant='example' user='bob'" {
+		t.Errorf("Unexpected message for parameterized event: \"%s\"", sink.events[1].Message)
+	}
+	if sink.events[2].Message != "no parameters" || sink.events[2].Parameters != nil {
+		t.Errorf("Bound parameters leaked to parent source: %+v", sink.events[2])
+	}
+}
+
+func TestSourceSub(t *testing.T) {
+	Setup()
+
+	b := &bytes.Buffer{}
+	logtic.Log.Stdout = b
+	logtic.Log.Color = nil
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	source := logtic.Log.Connect("App").Sub("db")
+	source.Info("connected")
+
+	if b.String() != "[INFO][App/db] connected\n" {
+		t.Errorf("Unexpected output: '%s'", b.String())
+	}
+
+	var nilSource *logtic.Source
+	nilSource.Sub("db").With(map[string]any{"key": "value"}).Info("nothing")
+}
+
+func TestSourceAdditionalLevels(t *testing.T) {
+	Setup()
+
+	dir := t.TempDir()
+
+	logtic.Log.FilePath = path.Join(dir, "logtic.log")
+	logtic.Log.Level = logtic.LevelTrace
+
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	source := logtic.Log.Connect("test")
+
+	source.Write(logtic.LevelTrace, "this is a %s message", "trace")
+	tracePattern := regexp.MustCompile(`[0-9\-:TZ]+ \[TRACE\]\[test\] this is a trace message`)
+	source.PWrite(logtic.LevelNotice, "notice event", map[string]any{"key": "value"})
+	noticePattern := regexp.MustCompile(`[0-9\-:TZ]+ \[NOTICE\]\[test\] notice event: key='value'`)
+	source.Critical("this is a %s message", "critical")
+	criticalPattern := regexp.MustCompile(`[0-9\-:TZ]+ \[CRITICAL\]\[test\] this is a critical message`)
+
+	logtic.Log.Level = logtic.LevelDebug
+	source.Trace("hidden trace message")
+	logtic.Log.Level = logtic.LevelCritical
+	source.Error("hidden error message")
+
+	logtic.Log.Close()
+
+	logFileData, err := os.ReadFile(path.Join(dir, "logtic.log"))
+	if err != nil {
+		panic(err)
+	}
+
+	if !tracePattern.Match(logFileData) {
+		t.Errorf("Log file does not contain expected log line for Trace message")
+	}
+	if !noticePattern.Match(logFileData) {
+		t.Errorf("Log file does not contain expected log line for Notice message")
+	}
+	if !criticalPattern.Match(logFileData) {
+		t.Errorf("Log file does not contain expected log line for Critical message")
+	}
+	if bytes.Contains(logFileData, []byte("hidden")) {
+		t.Errorf("Log file contains log line that should not exist")
+	}
+	if t.Failed() {
+		fmt.Printf("Log file data:\n%s\n", logFileData)
+	}
+}
+
+func TestSourceLevelRules(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelError
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+
+	logtic.Log.SetSourceLevel("http.*", logtic.LevelDebug)
+	logtic.Log.SetSourceLevel("http.client*", logtic.LevelWarn)
+	logtic.Log.SetSourceLevel("http.client.tls", logtic.LevelInfo)
+	logtic.Log.SetSourceLevel("db?", logtic.LevelInfo)
+
+	overridden := logtic.Log.Connect("http.server")
+	overridden.OverrideLevel(logtic.LevelError)
+
+	check := func(source *logtic.Source, level logtic.LogLevel, expected bool) {
+		before := len(sink.events)
+		source.Write(level, "message")
+		if logged := len(sink.events) > before; logged != expected {
+			t.Errorf("Unexpected result for %s event from '%s'. Expected logged=%v", level, source.Name, expected)
+		}
+	}
+
+	check(logtic.Log.Connect("http.router"), logtic.LevelDebug, true)
+	check(logtic.Log.Connect("http.router"), logtic.LevelTrace, false)
+	check(logtic.Log.Connect("http.client.pool"), logtic.LevelInfo, false)
+	check(logtic.Log.Connect("http.client.pool"), logtic.LevelWarn, true)
+	check(logtic.Log.Connect("http.client.tls"), logtic.LevelInfo, true)
+	check(logtic.Log.Connect("db1"), logtic.LevelInfo, true)
+	check(logtic.Log.Connect("db10"), logtic.LevelInfo, false)
+	check(logtic.Log.Connect("other"), logtic.LevelWarn, false)
+	check(overridden, logtic.LevelDebug, false)
+
+	logtic.Log.ClearSourceLevel("http.*")
+	check(logtic.Log.Connect("http.router"), logtic.LevelDebug, false)
+	check(logtic.Log.Connect("http.client.pool"), logtic.LevelWarn, true)
+}
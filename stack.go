@@ -0,0 +1,136 @@
+package logtic
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxStackFrames is the maximum number of frames included in a stack trace
+const maxStackFrames = 64
+
+// stackTrace returns the stack of the current goroutine, starting from the first frame outside of logtic, the standard
+// logging packages, and the runtime. Each frame is formatted as the function name followed by a line with a tab and
+// the file path and line number, the same as runtime/debug.Stack without arguments.
+func stackTrace() string {
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	b := &strings.Builder{}
+	top := true
+	for {
+		frame, more := frames.Next()
+		pkg := functionPackage(frame.Function)
+		if top && (callerSkipPackages[pkg] || pkg == "runtime") {
+			if !more {
+				break
+			}
+			continue
+		}
+		top = false
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function + "\n\t" + frame.File + ":" + strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// indentStack returns the stack with each line indented by a tab and preceded by a newline, for appending to a line
+// of text output
+func indentStack(stack string) string {
+	return "\n\t" + strings.ReplaceAll(stack, "\n", "\n\t")
+}
+
+// Recover will log any recovered panic along with the stack of where the panic happened as a fatal event, then panic
+// again with the same value. Recover must be called directly by defer, for example:
+//
+//	defer source.Recover()
+//
+// Use RecoverAndContinue to recover from the panic without panicking again.
+func (s *Source) Recover() {
+	if r := recover(); r != nil {
+		s.recovered(levelFatal, r)
+		panic(r)
+	}
+}
+
+// RecoverAndContinue will log any recovered panic along with the stack of where the panic happened as a critical
+// event, then resume normally. RecoverAndContinue must be called directly by defer, for example:
+//
+//	defer source.RecoverAndContinue()
+func (s *Source) RecoverAndContinue() {
+	if r := recover(); r != nil {
+		s.recovered(LevelCritical, r)
+	}
+}
+
+func (s *Source) recovered(level LogLevel, r any) {
+	defer s.panicRecover()
+	if s == nil || s.instance == nil || !s.instance.opened || (level != levelFatal && s.checkLevel(level)) {
+		fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n", r, debug.Stack())
+		return
+	}
+
+	event := s.newEvent(level, "panic: %v", r)
+	event.Stack = stackTrace()
+	s.write(event)
+}
+
+// panicRecover recovers from a panic while writing an event. The panic and its stack are printed to stderr and, if
+// possible, written to the log file.
+func (s *Source) panicRecover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	var instance *Logger
+	var name string
+	if s != nil {
+		instance, name = s.instance, s.Name
+	}
+	instance.recoveredPanic(name, r)
+}
+
+// panicRecover recovers from a panic while writing an event for the given source in the background, the same as
+// Source.panicRecover
+func (l *Logger) panicRecover(source string) {
+	if r := recover(); r != nil {
+		l.recoveredPanic(source, r)
+	}
+}
+
+func (l *Logger) recoveredPanic(source string, r any) {
+	stack := debug.Stack()
+	fmt.Fprintf(os.Stderr, "logtic: recovered from panic writing event. stack to follow.\n%s", stack)
+	if l != nil {
+		l.writeRecoveredPanic(source, r, string(stack))
+	}
+}
+
+// writeRecoveredPanic writes a panic that happened while writing an event directly to the log file, bypassing the
+// formatter and sinks in case they caused the panic
+func (l *Logger) writeRecoveredPanic(source string, r any, stack string) {
+	defer func() {
+		recover()
+	}()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return
+	}
+	line := fmt.Sprintf("%s [%s][%s] logtic: recovered from panic writing event: %v%s\n",
+		time.Now().Format(time.RFC3339), LevelError.String(), source, r, indentStack(strings.TrimSpace(stack)))
+	n, _ := l.file.Write([]byte(line))
+	l.fileSize += int64(n)
+}
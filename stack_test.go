@@ -0,0 +1,132 @@
+package logtic_test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestStack(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Options.Stack = true
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.Warn("warn message")
+	source.Error("error message")
+	logtic.Log.Close()
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	if sink.events[0].Stack != "" {
+		t.Errorf("Stack captured for event below stack level")
+	}
+	if !strings.HasPrefix(sink.events[1].Stack, "github.com/ecnepsnai/logtic_test.TestStack\n\t") {
+		t.Errorf("Unexpected stack:\n%s", sink.events[1].Stack)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if !strings.Contains(string(data), "error message\n\tgithub.com/ecnepsnai/logtic_test.TestStack\n\t\t") {
+		t.Errorf("Stack not written to log file:\n%s", data)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	Setup()
+
+	logtic.Log.Level = logtic.LevelError
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	source := logtic.Log.Connect("test")
+
+	panicked := func() (r any) {
+		defer func() {
+			r = recover()
+		}()
+		func() {
+			defer source.Recover()
+			panic("boom")
+		}()
+		return nil
+	}()
+	if panicked != "boom" {
+		t.Errorf("Recover did not panic again, got %v", panicked)
+	}
+
+	func() {
+		defer source.RecoverAndContinue()
+		panic("bang")
+	}()
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	if sink.events[0].Message != "panic: boom" || sink.events[0].Level.String() != "FATAL" {
+		t.Errorf("Unexpected event for recovered panic: %+v", sink.events[0])
+	}
+	if sink.events[1].Message != "panic: bang" || sink.events[1].Level != logtic.LevelCritical {
+		t.Errorf("Unexpected event for recovered panic: %+v", sink.events[1])
+	}
+	for _, event := range sink.events {
+		if !strings.HasPrefix(event.Stack, "github.com/ecnepsnai/logtic_test.TestRecover.func") {
+			t.Errorf("Stack does not start where the panic happened:\n%s", event.Stack)
+		}
+	}
+
+	func() {
+		defer source.RecoverAndContinue()
+	}()
+	if len(sink.events) != 2 {
+		t.Errorf("Event written without a panic")
+	}
+}
+
+type panicSink struct{}
+
+func (panicSink) WriteEvent(event logtic.Event) error {
+	panic("sink panic")
+}
+
+func TestPanicRecoverLogFile(t *testing.T) {
+	Setup()
+
+	logPath := path.Join(t.TempDir(), "logtic.log")
+	logtic.Log.FilePath = logPath
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+	logtic.Log.AddSink(panicSink{}, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.Info("message")
+	logtic.Log.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Error reading log file: %s", err.Error())
+	}
+	if !strings.Contains(string(data), "[ERROR][test] logtic: recovered from panic writing event: sink panic\n\t") {
+		t.Errorf("Recovered panic not written to log file:\n%s", data)
+	}
+}
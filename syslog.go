@@ -0,0 +1,339 @@
+package logtic
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFormat describes the message format used by a syslog sink
+type SyslogFormat int
+
+const (
+	// SyslogRFC5424 formats messages following RFC 5424. Parameters, the caller, and the trace context are included as
+	// structured data.
+	SyslogRFC5424 = SyslogFormat(0)
+	// SyslogRFC3164 formats messages following the legacy BSD syslog format from RFC 3164.
+	SyslogRFC3164 = SyslogFormat(1)
+)
+
+// SyslogFacility describes the syslog facility used by a syslog sink
+type SyslogFacility int
+
+const (
+	// SyslogFacilityUser user-level messages
+	SyslogFacilityUser = SyslogFacility(1)
+	// SyslogFacilityDaemon system daemon messages
+	SyslogFacilityDaemon = SyslogFacility(3)
+	// SyslogFacilityLocal0 local use 0
+	SyslogFacilityLocal0 = SyslogFacility(16)
+	// SyslogFacilityLocal1 local use 1
+	SyslogFacilityLocal1 = SyslogFacility(17)
+	// SyslogFacilityLocal2 local use 2
+	SyslogFacilityLocal2 = SyslogFacility(18)
+	// SyslogFacilityLocal3 local use 3
+	SyslogFacilityLocal3 = SyslogFacility(19)
+	// SyslogFacilityLocal4 local use 4
+	SyslogFacilityLocal4 = SyslogFacility(20)
+	// SyslogFacilityLocal5 local use 5
+	SyslogFacilityLocal5 = SyslogFacility(21)
+	// SyslogFacilityLocal6 local use 6
+	SyslogFacilityLocal6 = SyslogFacility(22)
+	// SyslogFacilityLocal7 local use 7
+	SyslogFacilityLocal7 = SyslogFacility(23)
+)
+
+// syslogStructuredDataID is the SD-ID used for parameters. 32473 is the private enterprise number reserved for
+// documentation use by RFC 5612.
+const syslogStructuredDataID = "logtic@32473"
+
+// syslogCallerID is the SD-ID used for the caller of an event
+const syslogCallerID = "logtic-caller@32473"
+
+// syslogTraceID is the SD-ID used for the trace context of an event
+const syslogTraceID = "logtic-trace@32473"
+
+// SyslogSink is a sink that sends events to a syslog server.
+//
+// The supported networks are "unixgram" or "unix" for a local syslog socket, "udp", "tcp", and "tls". Messages sent
+// over TCP or TLS are framed using octet counting from RFC 6587, and messages sent over a "unix" stream socket are
+// terminated by a newline. If the network and address are empty, the sink connects to the first local syslog socket
+// found (such as /dev/log).
+//
+// The severity of each message is based on the level of the event. The source name of the event is used as the MSGID
+// for RFC 5424 messages. When no AppName is set, the source name is also used as the APP-NAME or TAG.
+type SyslogSink struct {
+	// Network is the network used to connect to the syslog server
+	Network string
+	// Address is the address of the syslog server
+	Address string
+	// TLSConfig is the TLS configuration used for the "tls" network. Defaults to verifying the server name from
+	// Address.
+	TLSConfig *tls.Config
+	// Format is the message format. Defaults to SyslogRFC5424.
+	Format SyslogFormat
+	// Facility is the syslog facility for all messages. Defaults to SyslogFacilityUser.
+	Facility SyslogFacility
+	// AppName is the APP-NAME (or TAG for RFC 3164) for all messages. Defaults to the name of the source.
+	AppName string
+	// Hostname is the hostname for all messages. Defaults to the hostname of the system.
+	Hostname string
+	// Timeout is the maximum amount of time to connect to the syslog server or to write a message. Events are written
+	// while the logging instance is locked, so a syslog server that is not responding delays all logging by up to this
+	// amount. Defaults to 5 seconds.
+	Timeout time.Duration
+
+	conn    net.Conn
+	network string
+	lock    sync.Mutex
+}
+
+var syslogLocalSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// NewSyslogSink will create a new syslog sink for the given network and address. The connection is established when
+// the first event is written.
+func NewSyslogSink(network, address string) *SyslogSink {
+	hostname, _ := os.Hostname()
+	return &SyslogSink{
+		Network:  network,
+		Address:  address,
+		Format:   SyslogRFC5424,
+		Facility: SyslogFacilityUser,
+		Hostname: hostname,
+	}
+}
+
+// syslogSeverity returns the syslog severity for the given level
+func syslogSeverity(level LogLevel) int {
+	switch level {
+	case LevelTrace, LevelDebug:
+		return 7
+	case LevelInfo:
+		return 6
+	case LevelNotice:
+		return 5
+	case LevelWarn:
+		return 4
+	case LevelError:
+		return 3
+	case LevelCritical, levelFatal:
+		return 2
+	}
+	return 5
+}
+
+func (s *SyslogSink) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return s.Timeout
+}
+
+func (s *SyslogSink) connect() error {
+	dialer := &net.Dialer{Timeout: s.timeout()}
+	if s.Network == "" && s.Address == "" {
+		for _, socket := range syslogLocalSockets {
+			for _, network := range []string{"unixgram", "unix"} {
+				conn, err := dialer.Dial(network, socket)
+				if err == nil {
+					s.conn = conn
+					s.network = network
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("no local syslog socket found")
+	}
+
+	if s.Network == "tls" {
+		config := s.TLSConfig
+		if config == nil {
+			config = &tls.Config{}
+		}
+		if config.ServerName == "" {
+			config = config.Clone()
+			host, _, err := net.SplitHostPort(s.Address)
+			if err != nil {
+				return err
+			}
+			config.ServerName = host
+		}
+		conn, err := tls.DialWithDialer(dialer, "tcp", s.Address, config)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		s.network = s.Network
+		return nil
+	}
+
+	conn, err := dialer.Dial(s.Network, s.Address)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.network = s.Network
+	return nil
+}
+
+func (s *SyslogSink) isLocal() bool {
+	return s.Network == "" || strings.HasPrefix(s.Network, "unix")
+}
+
+// WriteEvent sends the event to the syslog server, connecting or reconnecting if needed
+func (s *SyslogSink) WriteEvent(event Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	message := s.format(event)
+
+	// Try once more with a new connection if the existing connection was lost
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err = s.connect(); err != nil {
+				return err
+			}
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(s.timeout()))
+		if _, err = s.conn.Write([]byte(s.frame(message))); err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// frame returns the message framed for the network of the current connection. Messages sent over TCP or TLS use
+// octet counting, and messages sent over a local stream socket are terminated by a newline.
+func (s *SyslogSink) frame(message string) string {
+	switch s.network {
+	case "tcp", "tcp4", "tcp6", "tls":
+		return strconv.Itoa(len(message)) + " " + message
+	case "unix":
+		return message + "\n"
+	}
+	return message
+}
+
+// Close closes the connection to the syslog server
+func (s *SyslogSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SyslogSink) format(event Event) string {
+	facility := s.Facility
+	if facility == 0 {
+		facility = SyslogFacilityUser
+	}
+	priority := int(facility)*8 + syslogSeverity(event.Level)
+
+	appName := s.AppName
+	if appName == "" {
+		appName = event.Source
+	}
+
+	if s.Format == SyslogRFC3164 {
+		tag := syslogToken(appName, 32)
+		if s.isLocal() {
+			return fmt.Sprintf("<%d>%s %s[%d]: %s", priority, event.Time.Format(time.Stamp), tag, os.Getpid(), event.Message)
+		}
+		return fmt.Sprintf("<%d>%s %s %s[%d]: %s", priority, event.Time.Format(time.Stamp), syslogToken(s.Hostname, 255), tag, os.Getpid(), event.Message)
+	}
+
+	message := event.Message
+	structuredData := ""
+	if len(event.Parameters) > 0 {
+		structuredData = syslogStructuredData(syslogStructuredDataID, event.Parameters)
+		if event.Event != "" {
+			message = event.Event
+		}
+	}
+	if event.Caller != nil {
+		caller := map[string]any{
+			"file": event.Caller.location(),
+		}
+		if event.Caller.Function != "" {
+			caller["function"] = event.Caller.Function
+		}
+		structuredData += syslogStructuredData(syslogCallerID, caller)
+	}
+	if event.Trace != nil {
+		structuredData += syslogStructuredData(syslogTraceID, map[string]any{
+			"trace_id":    event.Trace.TraceID,
+			"span_id":     event.Trace.SpanID,
+			"trace_flags": event.Trace.flags(),
+		})
+	}
+	if structuredData == "" {
+		structuredData = "-"
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		priority,
+		event.Time.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogToken(s.Hostname, 255),
+		syslogToken(appName, 48),
+		os.Getpid(),
+		syslogToken(event.Source, 32),
+		structuredData,
+		message)
+}
+
+// syslogToken returns s with any characters not permitted in a syslog header field replaced, truncated to length.
+func syslogToken(s string, length int) string {
+	if s == "" {
+		return "-"
+	}
+	token := []byte(s)
+	for i, c := range token {
+		if c < 33 || c > 126 {
+			token[i] = '_'
+		}
+	}
+	if len(token) > length {
+		token = token[:length]
+	}
+	return string(token)
+}
+
+// syslogStructuredData returns an RFC 5424 SD-ELEMENT with the given ID and all of the parameters
+func syslogStructuredData(id string, parameters map[string]any) string {
+	keys := make([]string, 0, len(parameters))
+	for k := range parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	out := "[" + id
+	for _, k := range keys {
+		name := []byte(k)
+		for i, c := range name {
+			if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+				name[i] = '_'
+			}
+		}
+		if len(name) > 32 {
+			name = name[:32]
+		}
+		value, _ := parameterString(parameters[k])
+		out += " " + string(name) + `="` + replacer.Replace(value) + `"`
+	}
+	return out + "]"
+}
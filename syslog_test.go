@@ -0,0 +1,235 @@
+package logtic_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+func TestSyslogSinkUDP(t *testing.T) {
+	Setup()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err.Error())
+	}
+	defer listener.Close()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := logtic.NewSyslogSink("udp", listener.LocalAddr().String())
+	sink.Hostname = "example.com"
+	sink.AppName = "myapp"
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.Warn("hello %s", "world")
+	source.PInfo("event", map[string]any{"key": `a "quoted" value]`, "number": 123})
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Error reading message: %s", err.Error())
+		}
+		return string(buf[:n])
+	}
+
+	warnPattern := regexp.MustCompile(fmt.Sprintf(`^<12>1 [0-9\-]+T[0-9:\.]+(Z|[+\-][0-9:]+) example.com myapp %d test - hello world$`, os.Getpid()))
+	if message := read(); !warnPattern.MatchString(message) {
+		t.Errorf("Unexpected syslog message: '%s'", message)
+	}
+
+	infoPattern := regexp.MustCompile(`^<14>1 \S+ example.com myapp [0-9]+ test \[logtic@32473 key="a \\"quoted\\" value\\]" number="123"\] event$`)
+	if message := read(); !infoPattern.MatchString(message) {
+		t.Errorf("Unexpected syslog message: '%s'", message)
+	}
+
+	logtic.Log.Close()
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	Setup()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err.Error())
+	}
+	defer listener.Close()
+
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			buf := make([]byte, n)
+			if _, err := reader.Read(buf); err != nil {
+				return
+			}
+			messages <- string(buf)
+		}
+	}()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := logtic.NewSyslogSink("tcp", listener.Addr().String())
+	sink.Format = logtic.SyslogRFC3164
+	sink.Facility = logtic.SyslogFacilityLocal0
+	sink.Hostname = "example.com"
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	source := logtic.Log.Connect("test")
+	source.Error("first")
+	source.Debug("second")
+	logtic.Log.Close()
+
+	pattern := regexp.MustCompile(`^<(131|135)>[A-Z][a-z]{2} [ 0-9]{2} [0-9:]{8} example.com test\[[0-9]+\]: (first|second)$`)
+	for _, expected := range []string{"<131>", "<135>"} {
+		message := <-messages
+		if !pattern.MatchString(message) || !strings.HasPrefix(message, expected) {
+			t.Errorf("Unexpected syslog message: '%s'", message)
+		}
+	}
+}
+
+func TestSyslogSinkTimeout(t *testing.T) {
+	// The server accepts connections but never reads from them, so writes stall once the socket buffers are full
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err.Error())
+	}
+	defer listener.Close()
+	conns := make(chan net.Conn, 100)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			conns <- conn
+		}
+	}()
+	defer func() {
+		listener.Close()
+		for conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	sink := logtic.NewSyslogSink("tcp", listener.Addr().String())
+	sink.Timeout = 50 * time.Millisecond
+	defer sink.Close()
+
+	event := logtic.Event{Time: time.Now(), Level: logtic.LevelInfo, Source: "test", Message: strings.Repeat("a", 64*1024)}
+	// A stalled write times out and the sink reconnects, so every write returns promptly
+	stalled := false
+	for i := 0; i < 1024 && !stalled; i++ {
+		start := time.Now()
+		sink.WriteEvent(event)
+		elapsed := time.Since(start)
+		if elapsed > 2*time.Second {
+			t.Fatalf("Write took %s", elapsed)
+		}
+		stalled = elapsed >= sink.Timeout
+	}
+	if !stalled {
+		t.Errorf("No write stalled when one was expected")
+	}
+}
+
+func TestSyslogSinkUnix(t *testing.T) {
+	Setup()
+
+	socketPath := path.Join(t.TempDir(), "log")
+	listener, err := net.ListenPacket("unixgram", socketPath)
+	if err != nil {
+		t.Skipf("Unix datagram sockets not supported: %s", err.Error())
+	}
+	defer listener.Close()
+
+	logtic.Log.Level = logtic.LevelDebug
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := logtic.NewSyslogSink("unixgram", socketPath)
+	sink.Format = logtic.SyslogRFC3164
+	logtic.Log.AddSink(sink, logtic.LevelDebug)
+
+	logtic.Log.Connect("test").Info("hello")
+
+	buf := make([]byte, 1024)
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Error reading message: %s", err.Error())
+	}
+	pattern := regexp.MustCompile(`^<14>[A-Z][a-z]{2} [ 0-9]{2} [0-9:]{8} test\[[0-9]+\]: hello$`)
+	if !pattern.Match(buf[:n]) {
+		t.Errorf("Unexpected syslog message: '%s'", buf[:n])
+	}
+
+	logtic.Log.Close()
+}
+
+func TestSyslogSinkUnixStream(t *testing.T) {
+	socketPath := path.Join(t.TempDir(), "log")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("Unix sockets not supported: %s", err.Error())
+	}
+	defer listener.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink := logtic.NewSyslogSink("unix", socketPath)
+	sink.Format = logtic.SyslogRFC3164
+	for _, message := range []string{"first", "second"} {
+		if err := sink.WriteEvent(logtic.Event{Time: time.Now(), Level: logtic.LevelInfo, Source: "test", Message: message}); err != nil {
+			t.Fatalf("Error writing event: %s", err.Error())
+		}
+	}
+	sink.Close()
+
+	// Each message is terminated by a newline so that they don't run together
+	for _, expected := range []string{"first", "second"} {
+		if line := <-lines; !strings.HasSuffix(line, ": "+expected) {
+			t.Errorf("Unexpected syslog message: '%s'", line)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package logtic
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TraceContext describes the W3C trace context of an event, used to correlate events with distributed traces
+type TraceContext struct {
+	// The trace ID as 32 lowercase hexadecimal characters
+	TraceID string
+	// The span ID as 16 lowercase hexadecimal characters
+	SpanID string
+	// The trace flags, where the lowest bit is set if the trace is sampled
+	TraceFlags byte
+}
+
+// TraceExtractor describes an interface for getting the trace context of the current span from a context, such as
+// from an OpenTelemetry span. For example:
+//
+//	logtic.RegisterTraceExtractor(logtic.TraceExtractorFunc(func(ctx context.Context) (logtic.TraceContext, bool) {
+//	    span := trace.SpanContextFromContext(ctx)
+//	    return logtic.TraceContext{
+//	        TraceID:    span.TraceID().String(),
+//	        SpanID:     span.SpanID().String(),
+//	        TraceFlags: byte(span.TraceFlags()),
+//	    }, span.IsValid()
+//	}))
+type TraceExtractor interface {
+	// TraceContext returns the trace context for ctx and true, or false if ctx has no trace context.
+	TraceContext(ctx context.Context) (TraceContext, bool)
+}
+
+// TraceExtractorFunc is a function that can be used as a TraceExtractor
+type TraceExtractorFunc func(ctx context.Context) (TraceContext, bool)
+
+// TraceContext calls f(ctx)
+func (f TraceExtractorFunc) TraceContext(ctx context.Context) (TraceContext, bool) {
+	return f(ctx)
+}
+
+type tTraceContextKey struct{}
+
+var (
+	traceExtractors    []TraceExtractor
+	traceExtractorLock sync.RWMutex
+)
+
+// RegisterTraceExtractor will register an extractor for the trace context of events written with a context, for all
+// logging instances. Extractors are used in the order they were registered, before any trace context added with
+// NewTraceContext.
+func RegisterTraceExtractor(extractor TraceExtractor) {
+	traceExtractorLock.Lock()
+	defer traceExtractorLock.Unlock()
+	traceExtractors = append(traceExtractors, extractor)
+}
+
+// NewTraceContext returns a copy of ctx carrying the given trace context, which is included in every event written
+// with the returned context, such as by Source.InfoCtx.
+func NewTraceContext(ctx context.Context, trace TraceContext) context.Context {
+	return context.WithValue(ctx, tTraceContextKey{}, trace)
+}
+
+// TraceContextFromContext returns the trace context for ctx from any registered extractors or that was added with
+// NewTraceContext.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+
+	traceExtractorLock.RLock()
+	extractors := traceExtractors
+	traceExtractorLock.RUnlock()
+	for _, extractor := range extractors {
+		if trace, ok := extractor.TraceContext(ctx); ok && trace.IsValid() {
+			return trace, true
+		}
+	}
+
+	trace, ok := ctx.Value(tTraceContextKey{}).(TraceContext)
+	return trace, ok && trace.IsValid()
+}
+
+// ParseTraceparent parses the value of a W3C traceparent header, such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseTraceparent(traceparent string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, fmt.Errorf("invalid traceparent '%s'", traceparent)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	// Future versions may add more fields, but version 00 has exactly four
+	if !isLowerHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent version '%s'", version)
+	}
+	if !isLowerHex(flags, 2) {
+		return TraceContext{}, fmt.Errorf("invalid traceparent flags '%s'", flags)
+	}
+	flagBytes, _ := hex.DecodeString(flags)
+
+	trace := TraceContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flagBytes[0],
+	}
+	if !trace.IsValid() {
+		return TraceContext{}, fmt.Errorf("invalid traceparent '%s'", traceparent)
+	}
+	return trace, nil
+}
+
+// IsValid returns true if the trace ID and span ID are valid and not all zeros
+func (t TraceContext) IsValid() bool {
+	return isLowerHex(t.TraceID, 32) && isLowerHex(t.SpanID, 16) &&
+		strings.Trim(t.TraceID, "0") != "" && strings.Trim(t.SpanID, "0") != ""
+}
+
+// Sampled returns true if the sampled flag is set
+func (t TraceContext) Sampled() bool {
+	return t.TraceFlags&0x01 == 0x01
+}
+
+// String returns the trace context as a W3C traceparent header value
+func (t TraceContext) String() string {
+	return "00-" + t.TraceID + "-" + t.SpanID + "-" + t.flags()
+}
+
+func (t TraceContext) flags() string {
+	return hex.EncodeToString([]byte{t.TraceFlags})
+}
+
+func isLowerHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package logtic_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ecnepsnai/logtic"
+)
+
+type spanKey struct{}
+
+func TestParseTraceparent(t *testing.T) {
+	trace, err := logtic.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("Error parsing traceparent: %s", err.Error())
+	}
+	if trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.SpanID != "00f067aa0ba902b7" || !trace.Sampled() {
+		t.Errorf("Unexpected trace context: %+v", trace)
+	}
+	if trace.String() != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Unexpected traceparent '%s'", trace.String())
+	}
+
+	if _, err := logtic.ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future"); err != nil {
+		t.Errorf("Error parsing future version traceparent: %s", err.Error())
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	}
+	for _, traceparent := range invalid {
+		if _, err := logtic.ParseTraceparent(traceparent); err == nil {
+			t.Errorf("No error parsing invalid traceparent '%s'", traceparent)
+		}
+	}
+}
+
+func TestTraceContext(t *testing.T) {
+	Setup()
+
+	logtic.RegisterTraceExtractor(logtic.TraceExtractorFunc(func(ctx context.Context) (logtic.TraceContext, bool) {
+		span, ok := ctx.Value(spanKey{}).(string)
+		return logtic.TraceContext{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: span}, ok
+	}))
+
+	logtic.Log.Level = logtic.LevelDebug
+	logtic.Log.Formatter = &logtic.TextFormatter{}
+	if err := logtic.Log.Open(); err != nil {
+		t.Fatalf("Error opening log file: %s", err.Error())
+	}
+
+	sink := &testSink{}
+	logtic.Log.AddSink(sink, logtic.LevelTrace)
+	source := logtic.Log.Connect("test")
+
+	trace, _ := logtic.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := logtic.NewTraceContext(context.Background(), trace)
+	source.InfoCtx(ctx, "from context")
+	source.PInfoCtx(context.WithValue(ctx, spanKey{}, "b7ad6b7169203331"), "from extractor", nil)
+	source.InfoCtx(context.Background(), "without trace")
+	source.Info("without context")
+
+	if len(sink.events) != 4 {
+		t.Fatalf("Unexpected number of events %d", len(sink.events))
+	}
+	if sink.events[0].Trace == nil || *sink.events[0].Trace != trace {
+		t.Errorf("Unexpected trace context: %+v", sink.events[0].Trace)
+	}
+	if sink.events[1].Trace == nil || sink.events[1].Trace.SpanID != "b7ad6b7169203331" || sink.events[1].Trace.Sampled() {
+		t.Errorf("Unexpected trace context: %+v", sink.events[1].Trace)
+	}
+	if sink.events[2].Trace != nil || sink.events[3].Trace != nil {
+		t.Errorf("Unexpected trace context for event without trace")
+	}
+
+	text := string((&logtic.TextFormatter{}).Format(sink.events[0]))
+	expected := "[INFO][test] from context trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 trace_flags=01"
+	if !strings.HasSuffix(text, expected) {
+		t.Errorf("Unexpected text output '%s'", text)
+	}
+	data := string((&logtic.JSONFormatter{}).Format(sink.events[0]))
+	expected = `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01"}`
+	if !strings.HasSuffix(data, expected) {
+		t.Errorf("Unexpected JSON output '%s'", data)
+	}
+}